@@ -16,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
 	"github.com/chia-network/chia-tools/internal/utils"
 )
 
@@ -31,7 +33,7 @@ chia-tools config add-trusted-peer 1.2.3.4 18444
 # You may also specify a DNS name. The tool will attempt to resolve the name to an IP address.
 # If the name resolves to multiple IP addresses, chia-tools will attempt to connect to each one to add it to the config.
 chia-tools config add-trusted-peer node.chia.net 8444`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
 		chiaRoot, err := config.GetChiaRootPath()
 		if err != nil {
 			slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
@@ -83,7 +85,7 @@ chia-tools config add-trusted-peer node.chia.net 8444`,
 		var errs []error
 		var successfulIPs []net.IP
 		for _, ip := range ips {
-			err = addTrustedPeer(cfg, chiaRoot, ip, port)
+			err = addTrustedPeer(cfg, cfgPath, chiaRoot, ip, port)
 			if err != nil {
 				errs = append(errs, err)
 				slogs.Logr.Error("error adding trusted peer", "peer", ip.String(), "error", err)
@@ -102,10 +104,10 @@ chia-tools config add-trusted-peer node.chia.net 8444`,
 		if len(successfulIPs) > 0 {
 			slogs.Logr.Info("Successfully added trusted peer", "successful_ips", len(successfulIPs), "failed_ips", len(errs))
 		}
-	},
+	}),
 }
 
-func addTrustedPeer(cfg *config.ChiaConfig, chiaRoot string, ip net.IP, port uint16) error {
+func addTrustedPeer(cfg *config.ChiaConfig, cfgPath, chiaRoot string, ip net.IP, port uint16) error {
 	peerIDStr, err := getPeerID(cfg, chiaRoot, ip, port)
 	if err != nil {
 		return err
@@ -134,7 +136,7 @@ func addTrustedPeer(cfg *config.ChiaConfig, chiaRoot string, ip net.IP, port uin
 		cfg.Wallet.FullNodePeers = append(cfg.Wallet.FullNodePeers, peerToAdd)
 	}
 
-	err = cfg.Save()
+	err = backups.SaveConfig(cfg, cfgPath, "config add-trusted-peer")
 	if err != nil {
 		return fmt.Errorf("error saving config: %w", err)
 	}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
+	"github.com/chia-network/chia-tools/internal/utils"
+)
+
+// backupsCmd represents the backups command
+var backupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "Lists and restores the config backups taken before chia-tools changes the config",
+}
+
+// backupsListCmd lists the config backups
+var backupsListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "Lists config backups, newest first",
+	Example: "chia-tools config backups list",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfgPath := backupConfigPath()
+		configBackups, err := backups.List(cfgPath)
+		if err != nil {
+			slogs.Logr.Fatal("error listing config backups", "error", err)
+		}
+		if len(configBackups) == 0 {
+			slogs.Logr.Info("No config backups found", "directory", backups.Dir(cfgPath))
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tCreated\tCommand\tSize")
+		for _, backup := range configBackups {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", backup.ID, backup.Created.Local().Format(time.DateTime), backup.Command, utils.HumanReadableSize(backup.Size))
+		}
+		_ = w.Flush()
+	},
+}
+
+// backupsRestoreCmd restores a config backup
+var backupsRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restores a config backup. The current config is backed up first, so a restore can be undone",
+	Example: `chia-tools config backups restore 20260102T150405.000Z.config-edit
+
+# A unique prefix of the id also works, such as just the timestamp
+chia-tools config backups restore 20260102T150405`,
+	Args: cobra.ExactArgs(1),
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
+		cfgPath := backupConfigPath()
+		backup, err := backups.Find(cfgPath, args[0])
+		if err != nil {
+			slogs.Logr.Fatal("error finding config backup", "error", err)
+		}
+
+		if viper.GetBool("dry-run") {
+			slogs.Logr.Info("DRY RUN: Would restore config from backup", "backup", backup.Path, "config", cfgPath)
+			return
+		}
+
+		if !utils.ConfirmAction(fmt.Sprintf("Are you sure you would like to replace %s with the backup from %s? (y/N)", cfgPath, backup.Created.Local().Format(time.DateTime)), skipConfirm) {
+			slogs.Logr.Error("Cancelled")
+			return
+		}
+
+		err = backups.Restore(cfgPath, backup, viper.GetInt("config-backups"))
+		if err != nil {
+			slogs.Logr.Fatal("error restoring config backup", "error", err)
+		}
+		slogs.Logr.Info("Restored config from backup. Restart your chia services for the configuration to take effect", "backup", backup.ID)
+	}),
+}
+
+// backupConfigPath returns the config file the backups belong to, from --config or CHIA_ROOT
+func backupConfigPath() string {
+	cfgPath := viper.GetString("config")
+	if cfgPath != "" {
+		return cfgPath
+	}
+
+	chiaRoot, err := config.GetChiaRootPath()
+	if err != nil {
+		slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
+	}
+	return path.Join(chiaRoot, "config", "config.yaml")
+}
+
+func init() {
+	backupsRestoreCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip confirmation")
+
+	backupsCmd.AddCommand(backupsListCmd)
+	backupsCmd.AddCommand(backupsRestoreCmd)
+	configCmd.AddCommand(backupsCmd)
+}
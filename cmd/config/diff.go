@@ -0,0 +1,281 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigArg can be used instead of a file name to compare with the default config
+const defaultConfigArg = "default"
+
+// Kinds of config differences
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+// configDifference is a single path that differs between two configs. Value is the value in the second config, in
+// the format `config edit --set` accepts.
+type configDifference struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	A      any    `json:"a,omitempty"`
+	B      any    `json:"b,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// diffCmd compares two chia configs
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compares two chia configuration files, or a configuration file with the default config",
+	Long: `Compares two chia configuration files, listing every path that was added, removed, or changed in the second one.
+Paths use the same dotted syntax as config edit --set, so the changes can be applied to the first config with config edit.
+Without arguments, the config in CHIA_ROOT is compared with the default config. "default" can be used in place of either file.
+With the default output, exits with status 1 if the configs differ. --as-set and --as-json exit with status 0, so their output can be captured by scripts.
+--as-set exits with status 2 if a path was removed, since --set can't remove it. --as-json lists removed paths with the change "removed".`,
+	Example: `# Show how the config in CHIA_ROOT differs from the default config
+chia-tools config diff
+
+# Show how another node's config differs from the default config
+chia-tools config diff node2/config.yaml
+
+# Show how to turn one node's config into another's, as arguments for config edit
+chia-tools config diff node1/config.yaml node2/config.yaml --as-set`,
+	Args: cobra.RangeArgs(0, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON := viper.GetBool("diff-as-json")
+		asSet := viper.GetBool("diff-as-set")
+		if asJSON && asSet {
+			slogs.Logr.Fatal("--as-json and --as-set can not be used together")
+		}
+		if asJSON || asSet {
+			// Keep stdout clean so the output can be piped into other tools
+			slogs.Init(viper.GetString("log-level"), slogs.WithWriter(os.Stderr))
+		}
+
+		var fileA, fileB string
+		switch len(args) {
+		case 0:
+			fileA = defaultConfigArg
+			fileB = viper.GetString("config")
+			if fileB == "" {
+				chiaRoot, err := config.GetChiaRootPath()
+				if err != nil {
+					slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
+				}
+				fileB = filepath.Join(chiaRoot, "config", "config.yaml")
+			}
+		case 1:
+			fileA = defaultConfigArg
+			fileB = args[0]
+		case 2:
+			fileA = args[0]
+			fileB = args[1]
+		}
+
+		cfgA, err := loadDiffConfig(fileA)
+		if err != nil {
+			slogs.Logr.Fatal("error loading config", "config", fileA, "error", err)
+		}
+		cfgB, err := loadDiffConfig(fileB)
+		if err != nil {
+			slogs.Logr.Fatal("error loading config", "config", fileB, "error", err)
+		}
+
+		differences, err := configDifferences(cfgA, cfgB)
+		if err != nil {
+			slogs.Logr.Fatal("error comparing configs", "error", err)
+		}
+
+		switch {
+		case asJSON:
+			if differences == nil {
+				differences = []configDifference{}
+			}
+			output, err := json.MarshalIndent(differences, "", "  ")
+			if err != nil {
+				slogs.Logr.Fatal("error marshalling differences", "error", err)
+			}
+			fmt.Println(string(output))
+		case asSet:
+			removed := printSetArgs(os.Stdout, differences)
+			if removed > 0 {
+				slogs.Logr.Error("the --set arguments are incomplete, since removed paths can not be set", "removed", removed)
+				os.Exit(exitCodeSetIncomplete)
+			}
+		case len(differences) == 0:
+			fmt.Println("Configs are identical")
+		default:
+			printConfigDifferences(os.Stdout, differences)
+			os.Exit(exitCodeConfigsDiffer)
+		}
+	},
+}
+
+// Exit codes for config diff. Differences are only reported in the exit status of the default output, so the output
+// of --as-set and --as-json can be captured by scripts that exit on errors.
+const (
+	// exitCodeConfigsDiffer is the exit code for the default output when the configs differ
+	exitCodeConfigsDiffer = 1
+	// exitCodeSetIncomplete is the exit code for --as-set when a path was removed, which can't be done with --set
+	exitCodeSetIncomplete = 2
+)
+
+// loadDiffConfig loads a config file, or the default config for defaultConfigArg
+func loadDiffConfig(file string) (*config.ChiaConfig, error) {
+	if file == defaultConfigArg {
+		return config.LoadDefaultConfig()
+	}
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	// Config files live in <CHIA_ROOT>/config/config.yaml
+	return config.LoadConfigAtRoot(absPath, filepath.Dir(filepath.Dir(absPath)))
+}
+
+// configDifferences walks both configs by their yaml keys, and lists every path that was added, removed, or changed
+// in b, sorted by path. Lists are compared as a whole, since --set replaces a whole list.
+func configDifferences(a, b *config.ChiaConfig) ([]configDifference, error) {
+	genericA, err := toGeneric(a)
+	if err != nil {
+		return nil, err
+	}
+	genericB, err := toGeneric(b)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := diffGeneric("", genericA, genericB)
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences, nil
+}
+
+// toGeneric converts a config to the maps, lists, and scalars of its yaml representation
+func toGeneric(cfg *config.ChiaConfig) (any, error) {
+	marshalled, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling config: %w", err)
+	}
+	var generic any
+	err = yaml.Unmarshal(marshalled, &generic)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+	}
+	return generic, nil
+}
+
+func diffGeneric(path string, a, b any) []configDifference {
+	mapA, aIsMap := a.(map[string]any)
+	mapB, bIsMap := b.(map[string]any)
+	if !aIsMap || !bIsMap {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []configDifference{{Path: path, Change: changeChanged, A: a, B: b, Value: setValue(b)}}
+	}
+
+	var differences []configDifference
+	for key, valueA := range mapA {
+		childPath := joinPath(path, key)
+		valueB, ok := mapB[key]
+		if !ok {
+			differences = append(differences, configDifference{Path: childPath, Change: changeRemoved, A: valueA})
+			continue
+		}
+		differences = append(differences, diffGeneric(childPath, valueA, valueB)...)
+	}
+	for key, valueB := range mapB {
+		if _, ok := mapA[key]; !ok {
+			differences = append(differences, configDifference{Path: joinPath(path, key), Change: changeAdded, B: valueB, Value: setValue(valueB)})
+		}
+	}
+	return differences
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// setValue formats a value the way `config edit --set` accepts it. Lists and maps are written as yaml flow
+// collections, which are also JSON.
+func setValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case map[string]any, []any:
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(marshalled)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// printConfigDifferences writes one line per difference, with the path and value ready to pass to --set
+func printConfigDifferences(w io.Writer, differences []configDifference) {
+	for _, difference := range differences {
+		switch difference.Change {
+		case changeAdded:
+			_, _ = fmt.Fprintf(w, "+ %s=%s\n", difference.Path, difference.Value)
+		case changeRemoved:
+			_, _ = fmt.Fprintf(w, "- %s (was %s)\n", difference.Path, setValue(difference.A))
+		case changeChanged:
+			_, _ = fmt.Fprintf(w, "~ %s=%s (was %s)\n", difference.Path, difference.Value, setValue(difference.A))
+		}
+	}
+}
+
+// safeShellArg matches arguments that do not need quoting in a shell
+var safeShellArg = regexp.MustCompile(`^[A-Za-z0-9_.,:/@=+-]+$`)
+
+// printSetArgs writes a --set argument for each added or changed path. Removed paths can't be set, so they are
+// logged instead. Returns the number of removed paths.
+func printSetArgs(w io.Writer, differences []configDifference) int {
+	removed := 0
+	for _, difference := range differences {
+		if difference.Change == changeRemoved {
+			slogs.Logr.Warn("path was removed, which can not be done with --set", "path", difference.Path)
+			removed++
+			continue
+		}
+		arg := fmt.Sprintf("%s=%s", difference.Path, difference.Value)
+		if !safeShellArg.MatchString(arg) {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		_, _ = fmt.Fprintf(w, "--set %s\n", arg)
+	}
+	return removed
+}
+
+func init() {
+	diffCmd.PersistentFlags().Bool("as-json", false, "Output the differences as JSON")
+	diffCmd.PersistentFlags().Bool("as-set", false, "Output the added and changed paths as --set arguments for config edit")
+
+	cobra.CheckErr(viper.BindPFlag("diff-as-json", diffCmd.PersistentFlags().Lookup("as-json")))
+	cobra.CheckErr(viper.BindPFlag("diff-as-set", diffCmd.PersistentFlags().Lookup("as-set")))
+
+	configCmd.AddCommand(diffCmd)
+}
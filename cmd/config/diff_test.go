@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDifferences(t *testing.T) {
+	a, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	b, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+
+	differences, err := configDifferences(a, b)
+	assert.NoError(t, err)
+	assert.Empty(t, differences)
+
+	b.FullNode.Port = 58444
+	b.FullNode.DNSServers = []string{"dns-1.example.com", "dns-2.example.com"}
+	b.NetworkOverrides.Config["examplenet"] = config.NetworkConfig{AddressPrefix: "txch"}
+	delete(b.NetworkOverrides.Config, "mainnet")
+
+	differences, err = configDifferences(a, b)
+	assert.NoError(t, err)
+
+	byPath := map[string]configDifference{}
+	for _, difference := range differences {
+		byPath[difference.Path] = difference
+	}
+	assert.Equal(t, changeChanged, byPath["full_node.port"].Change)
+	assert.Equal(t, "58444", byPath["full_node.port"].Value)
+	assert.Equal(t, `["dns-1.example.com","dns-2.example.com"]`, byPath["full_node.dns_servers"].Value)
+	assert.Equal(t, changeAdded, byPath["network_overrides.config.examplenet"].Change)
+	assert.Contains(t, byPath["network_overrides.config.examplenet"].Value, `"address_prefix":"txch"`)
+	assert.Equal(t, changeRemoved, byPath["network_overrides.config.mainnet"].Change)
+
+	// Every added and changed path can be applied with --set to turn a into b
+	for _, difference := range differences {
+		if difference.Change == changeRemoved {
+			continue
+		}
+		var pathSlice []string
+		for _, pathSlice = range config.ParsePathsFromStrings([]string{difference.Path}, false) {
+			break
+		}
+		assert.NoError(t, a.SetFieldByPath(pathSlice, difference.Value), difference.Path)
+	}
+	assert.Equal(t, b.FullNode.Port, a.FullNode.Port)
+	assert.Equal(t, b.FullNode.DNSServers, a.FullNode.DNSServers)
+	assert.Equal(t, b.NetworkOverrides.Config["examplenet"], a.NetworkOverrides.Config["examplenet"])
+}
+
+func TestPrintSetArgs(t *testing.T) {
+	slogs.Init("info")
+	var out bytes.Buffer
+	removed := printSetArgs(&out, []configDifference{
+		{Path: "full_node.port", Change: changeChanged, Value: "58444"},
+		{Path: "full_node.dns_servers", Change: changeChanged, Value: `["dns.example.com"]`},
+		{Path: "network_overrides.config.mainnet", Change: changeRemoved},
+	})
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, "--set full_node.port=58444\n--set 'full_node.dns_servers=[\"dns.example.com\"]'\n", out.String())
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/chia-network/go-modules/pkg/slogs"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
 )
 
 // editCmd generates a new chia config
@@ -20,7 +23,7 @@ chia-tools config edit --set full_node.port=58444 --set full_node.target_peer_co
 
 # Show what changes would be made without actually making them
 chia-tools config edit --set full_node.port=58444 --dry-run`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
 		chiaRoot, err := config.GetChiaRootPath()
 		if err != nil {
 			slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
@@ -91,11 +94,11 @@ chia-tools config edit --set full_node.port=58444 --dry-run`,
 			return
 		}
 
-		err = cfg.Save()
+		err = backups.SaveConfig(cfg, cfgPath, "config edit")
 		if err != nil {
 			slogs.Logr.Fatal("error saving config", "error", err)
 		}
-	},
+	}),
 }
 
 func init() {
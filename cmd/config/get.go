@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configValue is a single value read from the config
+type configValue struct {
+	Path  string
+	Value any
+}
+
+// getCmd reads values from a chia config
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Reads values from a chia configuration file by path",
+	Long: `Reads values from a chia configuration file by path, using the same dotted syntax as config edit --set.
+Each part of a path may use wildcards, such as *.rpc_port, to read every matching path.
+A single path without wildcards prints just the value. Otherwise, each value is printed as path=value.`,
+	Example: `chia-tools config get full_node.port
+
+# Read several paths at once
+chia-tools config get full_node.port wallet.trusted_peers
+
+# Read the RPC port of every service as JSON
+chia-tools config get '*.rpc_port' --as-json`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON := viper.GetBool("get-as-json")
+		asYAML := viper.GetBool("get-as-yaml")
+		if asJSON && asYAML {
+			slogs.Logr.Fatal("--as-json and --as-yaml can not be used together")
+		}
+
+		chiaRoot, err := config.GetChiaRootPath()
+		if err != nil {
+			slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
+		}
+
+		cfgPath := viper.GetString("config")
+		if cfgPath == "" {
+			// Use default chia root
+			cfgPath = path.Join(chiaRoot, "config", "config.yaml")
+		}
+
+		cfg, err := config.LoadConfigAtRoot(cfgPath, chiaRoot)
+		if err != nil {
+			slogs.Logr.Fatal("error loading chia config", "error", err)
+		}
+
+		generic, err := toGeneric(cfg)
+		if err != nil {
+			slogs.Logr.Fatal("error reading chia config", "error", err)
+		}
+
+		var values []configValue
+		seen := map[string]bool{}
+		for _, pattern := range args {
+			matches := matchPaths(generic, "", strings.Split(pattern, "."))
+			if len(matches) == 0 {
+				slogs.Logr.Fatal("path not found in config", "path", pattern)
+			}
+			// Paths matched by more than one pattern are only output once
+			for _, match := range matches {
+				if !seen[match.Path] {
+					seen[match.Path] = true
+					values = append(values, match)
+				}
+			}
+		}
+
+		switch {
+		case asJSON:
+			err = printValuesJSON(os.Stdout, values)
+		case asYAML:
+			err = printValuesYAML(os.Stdout, values)
+		case len(args) == 1 && !hasWildcard(args[0]):
+			_, err = fmt.Fprintln(os.Stdout, setValue(values[0].Value))
+		default:
+			for _, value := range values {
+				_, err = fmt.Fprintf(os.Stdout, "%s=%s\n", value.Path, setValue(value.Value))
+				if err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			slogs.Logr.Fatal("error writing values", "error", err)
+		}
+	},
+}
+
+// matchPaths returns every value in the generic config under prefix that matches the remaining pattern parts, in
+// path order. Each part is matched against a single key with path.Match.
+func matchPaths(value any, prefix string, parts []string) []configValue {
+	if len(parts) == 0 {
+		return []configValue{{Path: prefix, Value: value}}
+	}
+
+	values, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var matches []configValue
+	for _, key := range keys {
+		matched, err := path.Match(parts[0], key)
+		if err != nil || !matched {
+			continue
+		}
+		matches = append(matches, matchPaths(values[key], joinPath(prefix, key), parts[1:])...)
+	}
+	return matches
+}
+
+func hasWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// printValuesJSON writes the values as a JSON object keyed by path
+func printValuesJSON(w io.Writer, values []configValue) error {
+	byPath := map[string]any{}
+	for _, value := range values {
+		byPath[value.Path] = value.Value
+	}
+	marshalled, err := json.MarshalIndent(byPath, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling values: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(marshalled))
+	return err
+}
+
+// printValuesYAML writes the values as a yaml mapping keyed by path, in the order they were read
+func printValuesYAML(w io.Writer, values []configValue) error {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, value := range values {
+		valueNode := &yaml.Node{}
+		err := valueNode.Encode(value.Value)
+		if err != nil {
+			return fmt.Errorf("error marshalling %s: %w", value.Path, err)
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: value.Path}, valueNode)
+	}
+	marshalled, err := yaml.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("error marshalling values: %w", err)
+	}
+	_, err = w.Write(marshalled)
+	return err
+}
+
+func init() {
+	getCmd.PersistentFlags().Bool("as-json", false, "Output the values as a JSON object keyed by path")
+	getCmd.PersistentFlags().Bool("as-yaml", false, "Output the values as yaml keyed by path")
+
+	cobra.CheckErr(viper.BindPFlag("get-as-json", getCmd.PersistentFlags().Lookup("as-json")))
+	cobra.CheckErr(viper.BindPFlag("get-as-yaml", getCmd.PersistentFlags().Lookup("as-yaml")))
+
+	configCmd.AddCommand(getCmd)
+}
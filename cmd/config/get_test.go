@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPaths(t *testing.T) {
+	cfg, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	cfg.FullNode.DNSServers = []string{"dns.example.com"}
+	generic, err := toGeneric(cfg)
+	assert.NoError(t, err)
+
+	matches := matchPaths(generic, "", strings.Split("full_node.dns_servers", "."))
+	assert.Equal(t, []configValue{{Path: "full_node.dns_servers", Value: []any{"dns.example.com"}}}, matches)
+
+	// A wildcard part matches every key at that level, in path order
+	matches = matchPaths(generic, "", strings.Split("*.rpc_port", "."))
+	var paths []string
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+	assert.Contains(t, paths, "full_node.rpc_port")
+	assert.Contains(t, paths, "wallet.rpc_port")
+	assert.True(t, sort.StringsAreSorted(paths))
+
+	assert.Empty(t, matchPaths(generic, "", strings.Split("full_node.missing", ".")))
+	assert.Empty(t, matchPaths(generic, "", strings.Split("full_node.port.nested", ".")))
+}
+
+func TestPrintValues(t *testing.T) {
+	values := []configValue{
+		{Path: "wallet.rpc_port", Value: 9256},
+		{Path: "full_node.dns_servers", Value: []any{"dns.example.com"}},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, printValuesYAML(&out, values))
+	assert.Equal(t, "wallet.rpc_port: 9256\nfull_node.dns_servers:\n    - dns.example.com\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printValuesJSON(&out, values))
+	assert.JSONEq(t, `{"wallet.rpc_port": 9256, "full_node.dns_servers": ["dns.example.com"]}`, out.String())
+}
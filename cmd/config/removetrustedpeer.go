@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
 	"github.com/chia-network/chia-tools/internal/utils"
 )
 
@@ -34,7 +36,7 @@ chia-tools config remove-trusted-peer node.chia.net 8444
 
 # You can also remove all trusted peers by specifying the --all flag
 chia-tools config remove-trusted-peer --all`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
 		chiaRoot, err := config.GetChiaRootPath()
 		if err != nil {
 			slogs.Logr.Fatal("Unable to determine CHIA_ROOT", "error", err)
@@ -52,7 +54,7 @@ chia-tools config remove-trusted-peer --all`,
 		}
 
 		if removeAll {
-			removeAllTrustedPeers(cfg)
+			removeAllTrustedPeers(cfg, cfgPath)
 			return
 		}
 
@@ -90,7 +92,7 @@ chia-tools config remove-trusted-peer --all`,
 
 		var errs []error
 		for _, ip := range ips {
-			err = removeTrustedPeer(cfg, chiaRoot, ip, port)
+			err = removeTrustedPeer(cfg, cfgPath, chiaRoot, ip, port)
 			if err != nil {
 				errs = append(errs, err)
 			}
@@ -101,10 +103,10 @@ chia-tools config remove-trusted-peer --all`,
 			}
 			os.Exit(1)
 		}
-	},
+	}),
 }
 
-func removeTrustedPeer(cfg *config.ChiaConfig, chiaRoot string, ip net.IP, port uint16) error {
+func removeTrustedPeer(cfg *config.ChiaConfig, cfgPath, chiaRoot string, ip net.IP, port uint16) error {
 	peerIDStr, err := getPeerID(cfg, chiaRoot, ip, port)
 	if err != nil {
 		return err
@@ -128,7 +130,7 @@ func removeTrustedPeer(cfg *config.ChiaConfig, chiaRoot string, ip net.IP, port
 	}
 	cfg.Wallet.FullNodePeers = fullNodePeers
 
-	err = cfg.Save()
+	err = backups.SaveConfig(cfg, cfgPath, "config remove-trusted-peer")
 	if err != nil {
 		return fmt.Errorf("error saving config: %w", err)
 	}
@@ -137,7 +139,7 @@ func removeTrustedPeer(cfg *config.ChiaConfig, chiaRoot string, ip net.IP, port
 	return nil
 }
 
-func removeAllTrustedPeers(cfg *config.ChiaConfig) {
+func removeAllTrustedPeers(cfg *config.ChiaConfig, cfgPath string) {
 	if !utils.ConfirmAction("Are you sure you would like to remove all trusted peers? (y/N)", skipConfirm) {
 		slogs.Logr.Error("Cancelled")
 		return
@@ -154,7 +156,7 @@ func removeAllTrustedPeers(cfg *config.ChiaConfig) {
 		Port: cfg.FullNode.Port,
 	})
 
-	err := cfg.Save()
+	err := backups.SaveConfig(cfg, cfgPath, "config remove-trusted-peer")
 	if err != nil {
 		slogs.Logr.Fatal("error saving config", "error", err)
 	}
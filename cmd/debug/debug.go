@@ -9,15 +9,17 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/chia-network/go-chia-libs/pkg/config"
-	"github.com/chia-network/go-chia-libs/pkg/rpc"
 	"github.com/chia-network/go-modules/pkg/slogs"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/chia-network/chia-tools/cmd"
 	"github.com/chia-network/chia-tools/cmd/network"
+	"github.com/chia-network/chia-tools/internal/probe"
+	"github.com/chia-network/chia-tools/internal/utils"
 )
 
 // Define a fixed column width for size
@@ -41,64 +43,99 @@ var exclusions = []string{
 var debugCmd = &cobra.Command{
 	Use:   "debug",
 	Short: "Outputs debugging information about Chia",
+	Example: `chia-tools debug
+
+# Show versions, networks, and running services for every node in an inventory file
+chia-tools debug --inventory nodes.yaml`,
 	Run: func(cmd *cobra.Command, args []string) {
+		timeout := viper.GetDuration("debug-timeout")
+		if viper.GetString("debug-inventory") != "" || viper.GetBool("debug-as-json") {
+			debugFleet(timeout, viper.GetBool("debug-as-json"))
+			return
+		}
+
+		// The node is only probed once, so every section describes the same moment, and a node that is down only
+		// costs a single timeout
+		target := probe.TargetFromFlags("debug")
+		fleet, err := probe.FleetFromFlags("debug", timeout)
+		if err != nil {
+			slogs.Logr.Fatal("error probing node", "error", err)
+		}
+		node := fleet[0]
+		if node.Err != nil {
+			slogs.Logr.Fatal("error probing node", "error", node.Err)
+		}
+
 		fmt.Println("# Version Information")
 		fmt.Println(strings.Repeat("-", 60)) // Separator
-		ShowVersionInfo()
+		ShowVersionInfo(os.Stdout, node)
 
 		fmt.Println("\n# Network Information")
 		fmt.Println(strings.Repeat("-", 60)) // Separator
-		network.ShowNetworkInfo()
+		network.ShowNodeNetworkInfo(os.Stdout, node)
 
 		fmt.Println("\n# Port Information")
 		fmt.Println(strings.Repeat("-", 60)) // Separator
-		debugPorts()
+		debugPorts(target)
 
 		fmt.Println("\n# RPC Server Status")
 		fmt.Println(strings.Repeat("-", 60)) // Separator
-		debugRPC()
+		debugRPC(os.Stdout, node)
 
 		fmt.Println("\n# File Sizes")
 		debugFileSizes()
 	},
 }
 
-func debugRPC() {
-	slogs.Logr.Debug("initializing websocket client")
-	websocketClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
-	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
+// debugFleet outputs the versions, networks, and status of the services on every node. Ports and file sizes are
+// skipped, since they only describe the local CHIA_ROOT.
+func debugFleet(timeout time.Duration, asJSON bool) {
+	if asJSON {
+		// Keep stdout clean so the output can be piped into other tools
+		slogs.Init(viper.GetString("log-level"), slogs.WithWriter(os.Stderr))
 	}
-	slogs.Logr.Debug("initializing http client")
-	rpcClient, err := rpc.NewClient(rpc.ConnectionModeHTTP, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
+	fleet, err := probe.FleetFromFlags("debug", timeout)
 	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
+		slogs.Logr.Fatal("error probing nodes", "error", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-	runningHelper(w, websocketClient.DaemonService, "Daemon")
-	runningHelper(w, rpcClient.FullNodeService, "Full Node")
-	runningHelper(w, rpcClient.WalletService, "Wallet")
-	runningHelper(w, rpcClient.FarmerService, "Farmer")
-	runningHelper(w, rpcClient.HarvesterService, "Harvester")
-	runningHelper(w, rpcClient.CrawlerService, "Crawler")
-	runningHelper(w, rpcClient.DataLayerService, "Data Layer")
-	runningHelper(w, rpcClient.TimelordService, "Timelord")
-	_ = w.Flush()
+	if asJSON {
+		err = probe.WriteFleetJSON(os.Stdout, fleet)
+		if err != nil {
+			slogs.Logr.Fatal("error writing results", "error", err)
+		}
+		return
+	}
+
+	fmt.Println("# Version Information")
+	fmt.Println(strings.Repeat("-", 60)) // Separator
+	ShowFleetVersionInfo(os.Stdout, fleet)
+
+	fmt.Println("\n# Network Information")
+	fmt.Println(strings.Repeat("-", 60)) // Separator
+	network.ShowFleetNetworkInfo(os.Stdout, fleet, false)
+
+	fmt.Println("\n# RPC Server Status")
+	fmt.Println(strings.Repeat("-", 60)) // Separator
+	probe.WriteFleetTable(os.Stdout, fleet, func(node probe.NodeResults, result probe.Result) string {
+		return probe.Status(result)
+	})
 }
 
-func runningHelper(w io.Writer, service hasVersionInfo, label string) {
-	running := "Running"
-	_, _, err := service.GetVersion(&rpc.GetVersionOptions{})
-	if err != nil {
-		slogs.Logr.Debug("error getting RPC Status from daemon", "error", err)
-		running = "Not Running"
+func debugRPC(w io.Writer, node probe.NodeResults) {
+	tw := tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	for _, result := range node.Results {
+		runningHelper(tw, result)
 	}
-	_, _ = fmt.Fprintln(w, label, "\t", running)
+	_ = tw.Flush()
 }
 
-func debugPorts() {
-	cfg, err := config.GetChiaConfig()
+func runningHelper(w io.Writer, result probe.Result) {
+	_, _ = fmt.Fprintln(w, result.Label, "\t", probe.Status(result))
+}
+
+func debugPorts(target probe.Target) {
+	cfg, err := target.Config()
 	if err != nil {
 		fmt.Println("Could not load config")
 		return
@@ -141,7 +178,7 @@ func debugFileSizes() {
 
 	// Print sorted files
 	for _, file := range files {
-		fmt.Printf("%-*s %s\n", sizeColumnWidth, humanReadableSize(file.Size), file.Path)
+		fmt.Printf("%-*s %s\n", sizeColumnWidth, utils.HumanReadableSize(file.Size), file.Path)
 	}
 }
 
@@ -184,26 +221,17 @@ func isExcluded(path string) bool {
 	return false
 }
 
-// humanReadableSize converts bytes into a human-friendly format (KB, MB, GB, etc.)
-func humanReadableSize(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
-
 func init() {
 	debugCmd.PersistentFlags().Bool("sort", false, "Sort the files largest first")
 	debugCmd.PersistentFlags().Bool("all-files", false, "Show all files. By default, some typically small files are excluded from the output")
+	debugCmd.PersistentFlags().Duration("timeout", probe.DefaultTimeout, "How long to wait for each service to respond")
+	debugCmd.PersistentFlags().Bool("as-json", false, "Output the versions, networks, and status of the services as JSON")
 
 	cobra.CheckErr(viper.BindPFlag("debug-sort", debugCmd.PersistentFlags().Lookup("sort")))
 	cobra.CheckErr(viper.BindPFlag("debug-all-files", debugCmd.PersistentFlags().Lookup("all-files")))
+	probe.AddFlags(debugCmd.PersistentFlags(), "debug")
+	cobra.CheckErr(viper.BindPFlag("debug-timeout", debugCmd.PersistentFlags().Lookup("timeout")))
+	cobra.CheckErr(viper.BindPFlag("debug-as-json", debugCmd.PersistentFlags().Lookup("as-json")))
 
 	cmd.RootCmd.AddCommand(debugCmd)
 }
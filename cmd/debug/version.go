@@ -1,54 +1,40 @@
 package debug
 
 import (
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"os"
 	"text/tabwriter"
 
-	"github.com/chia-network/go-chia-libs/pkg/rpc"
-	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/chia-network/chia-tools/internal/probe"
 )
 
-// ShowVersionInfo outputs the running version for all services
-func ShowVersionInfo() {
-	slogs.Logr.Debug("initializing websocket client")
-	websocketClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
-	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
+// ShowVersionInfo outputs the running version for all services on a node that was probed
+func ShowVersionInfo(w io.Writer, node probe.NodeResults) {
+	tw := tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	for _, result := range node.Results {
+		versionHelper(tw, result)
 	}
-	slogs.Logr.Debug("initializing http client")
-	rpcClient, err := rpc.NewClient(rpc.ConnectionModeHTTP, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
-	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
-	}
-
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-	versionHelper(w, websocketClient.DaemonService, "Daemon")
-	versionHelper(w, rpcClient.FullNodeService, "Full Node")
-	versionHelper(w, rpcClient.WalletService, "Wallet")
-	versionHelper(w, rpcClient.FarmerService, "Farmer")
-	versionHelper(w, rpcClient.HarvesterService, "Harvester")
-	versionHelper(w, rpcClient.CrawlerService, "Crawler")
-	versionHelper(w, rpcClient.DataLayerService, "Data Layer")
-	versionHelper(w, rpcClient.TimelordService, "Timelord")
-	_ = w.Flush()
+	_ = tw.Flush()
 }
 
-type hasVersionInfo interface {
-	GetVersion(opts *rpc.GetVersionOptions) (*rpc.GetVersionResponse, *http.Response, error)
+func versionHelper(w io.Writer, result probe.Result) {
+	version := result.Version
+	switch {
+	case errors.Is(result.Err, probe.ErrTimeout):
+		version = "Not Responding"
+	case !result.Running:
+		version = "Not Running"
+	}
+	_, _ = fmt.Fprintln(w, result.Label, "\t", version)
 }
 
-func versionHelper(w io.Writer, service hasVersionInfo, label string) {
-	version, _, err := service.GetVersion(&rpc.GetVersionOptions{})
-	if err != nil {
-		slogs.Logr.Debug("error getting network info from daemon", "error", err)
-		version = &rpc.GetVersionResponse{Version: "Not Running"}
-	}
-	if version == nil {
-		slogs.Logr.Debug("no network info found", "service", label)
-		version = &rpc.GetVersionResponse{Version: "Not Running"}
-	}
-	_, _ = fmt.Fprintln(w, label, "\t", version.Version)
+// ShowFleetVersionInfo outputs the running version of every service on every node as a single table
+func ShowFleetVersionInfo(w io.Writer, fleet []probe.NodeResults) {
+	probe.WriteFleetTable(w, fleet, func(node probe.NodeResults, result probe.Result) string {
+		if !result.Running {
+			return probe.Status(result)
+		}
+		return result.Version
+	})
 }
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/internal/lock"
+)
+
+// WithChiaRootLock wraps the Run function of a command that modifies CHIA_ROOT, so the command holds the lock on
+// CHIA_ROOT while it runs
+func WithChiaRootLock(run func(cmd *cobra.Command, args []string)) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		defer lockChiaRoot(cmd.CommandPath())()
+		run(cmd, args)
+	}
+}
+
+// lockChiaRoot takes the lock on CHIA_ROOT for a command that modifies it, so commands run at the same time, such as
+// from cron, do not overwrite each other's changes. Returns a function that releases the lock. If the command exits
+// through a fatal error, the lock is left behind and removed by the next command, since its process is gone.
+// Nothing is locked for --dry-run.
+func lockChiaRoot(command string) func() {
+	if viper.GetBool("dry-run") {
+		return func() {}
+	}
+
+	chiaRoot, err := config.GetChiaRootPath()
+	if err != nil {
+		slogs.Logr.Fatal("error determining chia root", "error", err)
+	}
+
+	chiaRootLock, err := lock.Acquire(chiaRoot, command, viper.GetDuration("lock-timeout"))
+	if err != nil {
+		slogs.Logr.Fatal("error locking chia root", "error", err)
+	}
+
+	return func() {
+		err := chiaRootLock.Release()
+		if err != nil {
+			slogs.Logr.Error("error releasing lock on chia root", "error", err)
+		}
+	}
+}
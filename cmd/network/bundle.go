@@ -0,0 +1,162 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/tls"
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// bundleRoles are the node roles that get their own CHIA_ROOT in a network bundle
+var bundleRoles = []string{"full_node", "farmer", "timelord", "introducer", "seeder"}
+
+// bundleHosts are the addresses the nodes in a bundle use to reach each other, such as container or host names
+type bundleHosts struct {
+	FullNode   string
+	Introducer string
+	Seeder     string
+}
+
+// writeNetworkBundle writes a CHIA_ROOT for every node role to dir. Each role gets a config.yaml with the network
+// selected and the settings for that role, and certificates signed by a private CA that is shared by the whole
+// bundle. The network definition is written alongside the roles so it can be imported elsewhere.
+func writeNetworkBundle(dir, networkName string, constants config.NetworkConstants, netConfig config.NetworkConfig, hosts bundleHosts, definition []byte, definitionFile string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error checking bundle directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("bundle directory %s is not empty", dir)
+	}
+
+	caDir := path.Join(dir, "ca")
+	err = os.MkdirAll(caDir, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating bundle directory: %w", err)
+	}
+
+	err = os.WriteFile(path.Join(dir, definitionFile), definition, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing network definition: %w", err)
+	}
+
+	privateCACrt, privateCAKey, err := tls.GenerateNewCA()
+	if err != nil {
+		return fmt.Errorf("error generating private CA: %w", err)
+	}
+	privateCACrtBytes, privateCAKeyBytes, err := tls.EncodeCertAndKeyToPEM(privateCACrt, privateCAKey)
+	if err != nil {
+		return fmt.Errorf("error encoding private CA: %w", err)
+	}
+	err = os.WriteFile(path.Join(caDir, "private_ca.crt"), privateCACrtBytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing private CA cert: %w", err)
+	}
+	err = os.WriteFile(path.Join(caDir, "private_ca.key"), privateCAKeyBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing private CA key: %w", err)
+	}
+
+	for _, role := range bundleRoles {
+		cfg, err := bundleConfig(role, networkName, constants, netConfig, hosts)
+		if err != nil {
+			return err
+		}
+
+		configDir := path.Join(dir, role, "config")
+		sslDir := path.Join(configDir, "ssl")
+		err = os.MkdirAll(sslDir, 0755)
+		if err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", role, err)
+		}
+
+		err = tls.GenerateAndWriteAllCerts(sslDir, privateCACrt, privateCAKey)
+		if err != nil {
+			return fmt.Errorf("error generating certificates for %s: %w", role, err)
+		}
+
+		err = cfg.SavePath(path.Join(configDir, "config.yaml"))
+		if err != nil {
+			return fmt.Errorf("error writing config for %s: %w", role, err)
+		}
+		slogs.Logr.Info("Wrote bundle for node role", "role", role, "path", path.Join(dir, role))
+	}
+
+	return nil
+}
+
+// bundleConfig returns a default chia config for a node role, with the network selected, every service pointed at the
+// other nodes in the bundle on the network's port, and the settings for the role applied
+func bundleConfig(role, networkName string, constants config.NetworkConstants, netConfig config.NetworkConfig, hosts bundleHosts) (*config.ChiaConfig, error) {
+	cfg, err := config.LoadDefaultConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading default config: %w", err)
+	}
+
+	setNetwork(cfg, networkName, constants, netConfig)
+
+	port := netConfig.DefaultFullNodePort
+	pathUpdates := networkPathUpdates(networkName, networkSettings{
+		Port:           port,
+		FullNodeHost:   hosts.FullNode,
+		IntroducerHost: hosts.Introducer,
+		DNSIntroducers: []string{hosts.Seeder},
+		BootstrapPeers: []string{hosts.FullNode},
+		StaticPeers:    []string{},
+		FullNodePeers:  []config.Peer{},
+		WalletFullNodePeers: []config.Peer{
+			{
+				Host: hosts.FullNode,
+				Port: port,
+			},
+		},
+		PeersFilePath:       fmt.Sprintf("db/peers-%s.dat", networkName),
+		WalletPeersFilePath: fmt.Sprintf("wallet/db/wallet_peers-%s.dat", networkName),
+	})
+	for configPath, value := range bundleRoleUpdates(role, hosts) {
+		pathUpdates[configPath] = value
+	}
+	for configPath, value := range pathUpdates {
+		err = cfg.SetFieldByPath(configPathSlice(configPath), value)
+		if err != nil {
+			return nil, fmt.Errorf("error setting %s in config for %s: %w", configPath, role, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// bundleRoleUpdates returns the config paths that are only set for a single node role, on top of the settings every
+// role shares
+func bundleRoleUpdates(role string, hosts bundleHosts) map[string]any {
+	switch role {
+	case "full_node":
+		// The other nodes reach the full node by its bundle host, so it doesn't need to open its port with UPnP
+		return map[string]any{
+			"full_node.enable_upnp": false,
+		}
+	case "timelord":
+		// The timelord launcher runs next to the timelord, so it is the only VDF client, and is reached locally
+		// whatever self_hostname is set to
+		return map[string]any{
+			"timelord.vdf_server.host":          "127.0.0.1",
+			"timelord.vdf_clients.ip":           []string{"127.0.0.1"},
+			"timelord.vdf_clients.ips_estimate": []uint32{150000},
+		}
+	case "introducer":
+		// The introducer is reached by the other nodes on its bundle host
+		return map[string]any{
+			"introducer.host": hosts.Introducer,
+		}
+	case "seeder":
+		// The seeder always serves the bundle's full node, even before the crawler has found it
+		return map[string]any{
+			"seeder.static_peers": []string{hosts.FullNode},
+		}
+	}
+	return nil
+}
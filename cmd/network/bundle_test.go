@@ -0,0 +1,75 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundleConfig(t *testing.T) {
+	constants := validTestConstants()
+	netConfig := config.NetworkConfig{
+		AddressPrefix:       "txch",
+		DefaultFullNodePort: 58445,
+	}
+	hosts := bundleHosts{
+		FullNode:   "node-1",
+		Introducer: "intro-1",
+		Seeder:     "seeder-1",
+	}
+
+	cfg, err := bundleConfig("farmer", "bundlenet", constants, netConfig, hosts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "bundlenet", *cfg.SelectedNetwork)
+	assert.Equal(t, constants, cfg.NetworkOverrides.Constants["bundlenet"])
+	assert.Equal(t, netConfig, cfg.NetworkOverrides.Config["bundlenet"])
+
+	expectedPeers := []config.Peer{{Host: "node-1", Port: 58445}}
+	assert.Equal(t, expectedPeers, cfg.Farmer.FullNodePeers)
+	assert.Equal(t, expectedPeers, cfg.Timelord.FullNodePeers)
+	assert.Equal(t, expectedPeers, cfg.Wallet.FullNodePeers)
+
+	assert.Equal(t, uint16(58445), cfg.FullNode.Port)
+	assert.Equal(t, uint16(58445), cfg.Introducer.Port)
+	assert.Equal(t, uint16(58445), cfg.Seeder.Port)
+	assert.Equal(t, config.Peer{Host: "intro-1", Port: 58445}, cfg.FullNode.IntroducerPeer)
+	assert.Equal(t, config.Peer{Host: "intro-1", Port: 58445}, cfg.Wallet.IntroducerPeer)
+	assert.Equal(t, []string{"seeder-1"}, cfg.FullNode.DNSServers)
+	assert.Equal(t, []string{"node-1"}, cfg.Seeder.BootstrapPeers)
+	assert.Equal(t, "db/blockchain_v2_bundlenet.sqlite", cfg.FullNode.DatabasePath)
+}
+
+func TestBundleConfig_Roles(t *testing.T) {
+	netConfig := config.NetworkConfig{
+		AddressPrefix:       "txch",
+		DefaultFullNodePort: 58445,
+	}
+	hosts := bundleHosts{
+		FullNode:   "node-1",
+		Introducer: "intro-1",
+		Seeder:     "seeder-1",
+	}
+	roleConfig := func(role string) *config.ChiaConfig {
+		cfg, err := bundleConfig(role, "bundlenet", validTestConstants(), netConfig, hosts)
+		assert.NoError(t, err)
+		return cfg
+	}
+
+	defaults, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	farmer := roleConfig("farmer")
+	assert.Equal(t, defaults.FullNode.EnableUPnP, farmer.FullNode.EnableUPnP)
+	assert.Equal(t, []string{}, farmer.Seeder.StaticPeers)
+
+	assert.False(t, roleConfig("full_node").FullNode.EnableUPnP)
+
+	timelord := roleConfig("timelord")
+	assert.Equal(t, "127.0.0.1", timelord.Timelord.VDFServer.Host)
+	assert.Equal(t, []string{"127.0.0.1"}, timelord.Timelord.VDFClients.IP)
+	assert.Len(t, timelord.Timelord.VDFClients.IPsEstimate, 1)
+
+	assert.Equal(t, "intro-1", roleConfig("introducer").Introducer.Host)
+	assert.Equal(t, []string{"node-1"}, roleConfig("seeder").Seeder.StaticPeers)
+}
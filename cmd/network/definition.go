@@ -0,0 +1,112 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// networkDefinition is a document that defines one or more networks. It accepts any of:
+//   - a full chia config.yaml, using its network_overrides section
+//   - the network_overrides document output by `network generate --with-constants`
+//
+// Either form may be YAML or JSON, and may include a network_profiles section with a profile for each network.
+type networkDefinition struct {
+	NetworkOverrides *config.NetworkOverrides           `yaml:"network_overrides,omitempty" json:"network_overrides,omitempty"`
+	Constants        map[string]config.NetworkConstants `yaml:"constants,omitempty" json:"constants,omitempty"`
+	Config           map[string]config.NetworkConfig    `yaml:"config,omitempty" json:"config,omitempty"`
+	NetworkProfiles  map[string]networkProfile          `yaml:"network_profiles,omitempty" json:"network_profiles,omitempty"`
+}
+
+// parseNetworkDefinition parses a YAML or JSON network definition
+func parseNetworkDefinition(data []byte) (*networkDefinition, error) {
+	definition := &networkDefinition{}
+
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(data, definition)
+	} else {
+		err = yaml.Unmarshal(data, definition)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling network definition: %w", err)
+	}
+
+	// Normalize the bare network_overrides form into the same shape as a full config
+	if definition.NetworkOverrides == nil {
+		if definition.Constants == nil && definition.Config == nil {
+			return nil, errors.New("network definition does not contain network_overrides, or network constants and config")
+		}
+		definition.NetworkOverrides = &config.NetworkOverrides{
+			Constants: definition.Constants,
+			Config:    definition.Config,
+		}
+	}
+	definition.Constants = nil
+	definition.Config = nil
+
+	// Network names are used in paths in CHIA_ROOT, so names from a downloaded definition must be checked before use
+	err = definition.validateNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return definition, nil
+}
+
+// validateNames checks every network name in the definition with validateNetworkName
+func (d *networkDefinition) validateNames() error {
+	for name := range d.NetworkOverrides.Constants {
+		if err := validateNetworkName(name); err != nil {
+			return fmt.Errorf("network definition has network constants with an %w", err)
+		}
+	}
+	for name := range d.NetworkOverrides.Config {
+		if err := validateNetworkName(name); err != nil {
+			return fmt.Errorf("network definition has network config with an %w", err)
+		}
+	}
+	for name := range d.NetworkProfiles {
+		if err := validateNetworkName(name); err != nil {
+			return fmt.Errorf("network definition has a network profile with an %w", err)
+		}
+	}
+	return nil
+}
+
+// networkNames returns every network name that has both constants and config in the definition
+func (d *networkDefinition) networkNames() []string {
+	var names []string
+	for name := range d.NetworkOverrides.Constants {
+		if _, ok := d.NetworkOverrides.Config[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// readSource reads from a file path, stdin when file is "-", or otherwise from the url
+func readSource(rawURL, file string, insecure bool) ([]byte, error) {
+	switch file {
+	case "":
+		return fetchURL(rawURL, insecure)
+	case "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("error reading stdin: %w", err)
+		}
+		return data, nil
+	default:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		return data, nil
+	}
+}
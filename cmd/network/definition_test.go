@@ -0,0 +1,78 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func testNetworkOverrides() *config.NetworkOverrides {
+	return &config.NetworkOverrides{
+		Constants: map[string]config.NetworkConstants{
+			"examplenet": {
+				GenesisChallenge: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				MinPlotSize:      18,
+			},
+		},
+		Config: map[string]config.NetworkConfig{
+			"examplenet": {
+				AddressPrefix:       "txch",
+				DefaultFullNodePort: 58445,
+			},
+		},
+	}
+}
+
+func TestParseNetworkDefinition(t *testing.T) {
+	overrides := testNetworkOverrides()
+
+	overridesYAML, err := yaml.Marshal(overrides)
+	assert.NoError(t, err)
+	overridesJSON, err := json.Marshal(overrides)
+	assert.NoError(t, err)
+	fullConfigYAML, err := yaml.Marshal(map[string]any{
+		"selected_network":  "mainnet",
+		"network_overrides": overrides,
+		"network_profiles": map[string]networkProfile{
+			"examplenet": {Introducer: "introducer.example.com"},
+		},
+	})
+	assert.NoError(t, err)
+
+	for name, data := range map[string][]byte{
+		"overrides yaml":   overridesYAML,
+		"overrides json":   overridesJSON,
+		"full config yaml": fullConfigYAML,
+	} {
+		t.Run(name, func(t *testing.T) {
+			definition, err := parseNetworkDefinition(data)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"examplenet"}, definition.networkNames())
+			assert.Equal(t, overrides.Config["examplenet"], definition.NetworkOverrides.Config["examplenet"])
+			assert.Equal(t, overrides.Constants["examplenet"].GenesisChallenge, definition.NetworkOverrides.Constants["examplenet"].GenesisChallenge)
+		})
+	}
+
+	definition, err := parseNetworkDefinition(fullConfigYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, "introducer.example.com", definition.NetworkProfiles["examplenet"].Introducer)
+
+	_, err = parseNetworkDefinition([]byte("selected_network: mainnet\n"))
+	assert.Error(t, err)
+}
+
+func TestParseNetworkDefinition_InvalidName(t *testing.T) {
+	for name, data := range map[string]string{
+		"constants": "constants:\n  '..':\n    GENESIS_CHALLENGE: e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855\nconfig:\n  '..':\n    address_prefix: txch\n",
+		"config":    "constants: {}\nconfig:\n  ../../mainnet:\n    address_prefix: txch\n",
+		"profile":   "constants: {}\nconfig: {}\nnetwork_profiles:\n  '':\n    introducer: introducer.example.com\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseNetworkDefinition([]byte(data))
+			assert.ErrorContains(t, err, "invalid network name")
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compares the constants and config of two networks",
+	Long:  "Compares the constants and config of two networks, listing every field that differs. Exits with status 2 if the networks differ.",
+	Example: `# Compare two networks in the local config
+chia-tools network diff testneta testnetb
+
+# Compare a local network with a published definition
+chia-tools network diff mytestnet --url https://example.com/my-network-config.yml
+chia-tools network diff mytestnet --file my-network-config.yml
+
+# Compare a local network with a published definition that uses a different name for it
+chia-tools network diff mytestnet --file my-network-config.yml --remote-network testnet-2024`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		url := viper.GetString("net-diff-url")
+		file := viper.GetString("net-diff-file")
+		remote := url != "" || file != ""
+		if url != "" && file != "" {
+			slogs.Logr.Fatal("Only one of --url or --file may be provided")
+		}
+		if remote == (len(args) == 2) {
+			slogs.Logr.Fatal("Provide either two networks, or one network and --url or --file")
+		}
+		if !remote && viper.GetString("net-diff-remote-network") != "" {
+			slogs.Logr.Fatal("--remote-network can only be used with --url or --file")
+		}
+
+		cfg, err := config.GetChiaConfig()
+		if err != nil {
+			slogs.Logr.Fatal("error loading config", "error", err)
+		}
+		localOverrides := cfg.NetworkOverrides
+		if localOverrides == nil {
+			localOverrides = &config.NetworkOverrides{}
+		}
+
+		nameA := args[0]
+		constantsA, configA, ok := lookupNetwork(localOverrides, nameA)
+		if !ok {
+			slogs.Logr.Fatal("network does not exist in the local config", "network", nameA)
+		}
+		labelA := nameA
+
+		var constantsB config.NetworkConstants
+		var configB config.NetworkConfig
+		var labelB string
+		if remote {
+			data, err := readSource(url, file, viper.GetBool("net-diff-insecure"))
+			if err != nil {
+				slogs.Logr.Fatal("Failed to load network definition", "error", err)
+			}
+			definition, err := parseNetworkDefinition(data)
+			if err != nil {
+				slogs.Logr.Fatal("Failed to parse network definition", "error", err)
+			}
+
+			nameB := nameA
+			if remoteNetwork := viper.GetString("net-diff-remote-network"); remoteNetwork != "" {
+				nameB = remoteNetwork
+			}
+			constantsB, configB, ok = lookupNetwork(definition.NetworkOverrides, nameB)
+			if !ok {
+				slogs.Logr.Fatal("network does not exist in the network definition. Use --remote-network to compare with a network of a different name", "network", nameB, "networks", definition.networkNames())
+			}
+			labelA = fmt.Sprintf("Local (%s)", nameA)
+			labelB = fmt.Sprintf("Remote (%s)", nameB)
+		} else {
+			nameB := args[1]
+			constantsB, configB, ok = lookupNetwork(localOverrides, nameB)
+			if !ok {
+				slogs.Logr.Fatal("network does not exist in the local config", "network", nameB)
+			}
+			labelB = nameB
+		}
+
+		differences := networkDifferences(constantsA, configA, constantsB, configB)
+
+		if viper.GetBool("net-diff-as-json") {
+			if differences == nil {
+				differences = []fieldDifference{}
+			}
+			output, err := json.MarshalIndent(differences, "", "  ")
+			if err != nil {
+				slogs.Logr.Fatal("error marshalling differences", "error", err)
+			}
+			fmt.Println(string(output))
+		} else if len(differences) == 0 {
+			fmt.Println("Networks are identical")
+		} else {
+			printFieldDiffs(os.Stdout, differences, labelA, labelB)
+		}
+
+		if len(differences) > 0 {
+			os.Exit(exitCodeNetworksDiffer)
+		}
+	},
+}
+
+// exitCodeNetworksDiffer is the exit code for `network diff` when the networks differ. It is distinct from the exit
+// code for other errors, so CI can tell a mismatch from a definition that failed to load.
+const exitCodeNetworksDiffer = 2
+
+// lookupNetwork returns the constants and config for a network. Returns false if the network has neither.
+func lookupNetwork(overrides *config.NetworkOverrides, network string) (config.NetworkConstants, config.NetworkConfig, bool) {
+	constants, hasConstants := overrides.Constants[network]
+	netConfig, hasConfig := overrides.Config[network]
+	return constants, netConfig, hasConstants || hasConfig
+}
+
+// networkDifferences lists every constant and config field that differs between two networks
+func networkDifferences(constantsA config.NetworkConstants, configA config.NetworkConfig, constantsB config.NetworkConstants, configB config.NetworkConfig) []fieldDifference {
+	differences := diffFields("constants", constantsA, constantsB)
+	return append(differences, diffFields("config", configA, configB)...)
+}
+
+func init() {
+	diffCmd.PersistentFlags().StringP("url", "u", "", "URL of a network definition to compare the local network with")
+	diffCmd.PersistentFlags().StringP("file", "f", "", "Path to a network definition to compare the local network with, or - to read from stdin")
+	diffCmd.PersistentFlags().Bool("insecure", false, "Allow loading the network definition over plain http")
+	diffCmd.PersistentFlags().Bool("as-json", false, "Output the differences as JSON")
+	diffCmd.PersistentFlags().String("remote-network", "", "Name of the network in the network definition, when it differs from the local network")
+
+	cobra.CheckErr(viper.BindPFlag("net-diff-url", diffCmd.PersistentFlags().Lookup("url")))
+	cobra.CheckErr(viper.BindPFlag("net-diff-file", diffCmd.PersistentFlags().Lookup("file")))
+	cobra.CheckErr(viper.BindPFlag("net-diff-insecure", diffCmd.PersistentFlags().Lookup("insecure")))
+	cobra.CheckErr(viper.BindPFlag("net-diff-as-json", diffCmd.PersistentFlags().Lookup("as-json")))
+	cobra.CheckErr(viper.BindPFlag("net-diff-remote-network", diffCmd.PersistentFlags().Lookup("remote-network")))
+
+	networkCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,39 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkDifferences(t *testing.T) {
+	overrides := testNetworkOverrides()
+	constantsA, configA, ok := lookupNetwork(overrides, "examplenet")
+	assert.True(t, ok)
+
+	_, _, ok = lookupNetwork(overrides, "missingnet")
+	assert.False(t, ok)
+
+	assert.Empty(t, networkDifferences(constantsA, configA, constantsA, configA))
+
+	constantsB := constantsA
+	constantsB.MinPlotSize = 20
+	constantsB.HardFork2Height = ptr.Uint32Ptr(100)
+	configB := configA
+	configB.DefaultFullNodePort = 58444
+
+	differences := networkDifferences(constantsA, configA, constantsB, configB)
+	assert.ElementsMatch(t, []fieldDifference{
+		{Field: "constants.MIN_PLOT_SIZE", A: uint8(18), B: uint8(20)},
+		{Field: "constants.HARD_FORK2_HEIGHT", A: nil, B: uint32(100)},
+		{Field: "config.default_full_node_port", A: uint16(58445), B: uint16(58444)},
+	}, differences)
+
+	// Networks that only exist in the config section are still found
+	overrides.Config["confignet"] = config.NetworkConfig{AddressPrefix: "tcfg"}
+	_, netConfig, ok := lookupNetwork(overrides, "confignet")
+	assert.True(t, ok)
+	assert.Equal(t, "tcfg", netConfig.AddressPrefix)
+}
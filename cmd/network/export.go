@@ -0,0 +1,155 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports a network definition that network import accepts",
+	Example: `chia-tools network export mytestnet > mytestnet.yaml
+
+# Include the peers this node uses for the network, and write JSON to a file
+chia-tools network export mytestnet --with-peers --as-json --output mytestnet.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		networkName := args[0]
+		output := viper.GetString("net-export-output")
+		if output == "" {
+			// Keep stdout clean so the definition can be piped straight into network import
+			slogs.Init(viper.GetString("log-level"), slogs.WithWriter(os.Stderr))
+		}
+
+		chiaRoot, err := config.GetChiaRootPath()
+		if err != nil {
+			slogs.Logr.Fatal("error determining chia root", "error", err)
+		}
+		slogs.Logr.Debug("Chia root discovered", "CHIA_ROOT", chiaRoot)
+
+		cfg, err := config.GetChiaConfig()
+		if err != nil {
+			slogs.Logr.Fatal("error loading config", "error", err)
+		}
+
+		definition, err := exportNetwork(chiaRoot, cfg, networkName, viper.GetBool("net-export-with-peers"))
+		if err != nil {
+			slogs.Logr.Fatal("error exporting network", "network", networkName, "error", err)
+		}
+
+		var marshalled []byte
+		if viper.GetBool("net-export-as-json") {
+			marshalled, err = json.MarshalIndent(definition, "", "  ")
+		} else {
+			marshalled, err = yaml.Marshal(definition)
+		}
+		if err != nil {
+			slogs.Logr.Fatal("error marshalling network definition", "error", err)
+		}
+
+		if output == "" {
+			fmt.Print(string(marshalled))
+			return
+		}
+		err = os.WriteFile(output, marshalled, 0644)
+		if err != nil {
+			slogs.Logr.Fatal("error writing network definition", "error", err)
+		}
+		slogs.Logr.Info("Exported network", "network", networkName, "path", output)
+	},
+}
+
+// exportNetwork returns a definition with only the constants and config of one network. With withPeers, the
+// definition also includes a profile with the peers used for the network. These come from the current config
+// when the network is selected, and otherwise from the settings retained when switching away from it.
+func exportNetwork(chiaRoot string, cfg *config.ChiaConfig, networkName string, withPeers bool) (*networkDefinition, error) {
+	if cfg.NetworkOverrides == nil {
+		return nil, errors.New("config does not have any network overrides")
+	}
+	constants, hasConstants := cfg.NetworkOverrides.Constants[networkName]
+	netConfig, hasConfig := cfg.NetworkOverrides.Config[networkName]
+	if !hasConstants || !hasConfig {
+		return nil, errors.New("network must have both constants and config in the network overrides")
+	}
+
+	definition := &networkDefinition{
+		NetworkOverrides: &config.NetworkOverrides{
+			Constants: map[string]config.NetworkConstants{
+				networkName: constants,
+			},
+			Config: map[string]config.NetworkConfig{
+				networkName: netConfig,
+			},
+		},
+	}
+	if !withPeers {
+		return definition, nil
+	}
+
+	profile, err := loadNetworkProfile(chiaRoot, networkName)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &networkProfile{}
+	}
+
+	if cfg.SelectedNetwork != nil && *cfg.SelectedNetwork == networkName {
+		profile.merge(&networkProfile{
+			Introducer:     cfg.FullNode.IntroducerPeer.Host,
+			DNSIntroducers: cfg.FullNode.DNSServers,
+			BootstrapPeers: cfg.Seeder.BootstrapPeers,
+			StaticPeers:    cfg.Seeder.StaticPeers,
+		})
+	} else {
+		settings, err := loadRetainedSettings(chiaRoot, networkName)
+		if err != nil {
+			return nil, err
+		}
+		if settings != nil {
+			retained := &networkProfile{}
+			for configPath, target := range map[string]any{
+				"full_node.dns_servers":  &retained.DNSIntroducers,
+				"seeder.bootstrap_peers": &retained.BootstrapPeers,
+				"seeder.static_peers":    &retained.StaticPeers,
+			} {
+				_, err = settings.decode(configPath, target)
+				if err != nil {
+					return nil, err
+				}
+			}
+			profile.merge(retained)
+		}
+	}
+
+	if profile.Introducer == "" && len(profile.DNSIntroducers) == 0 && len(profile.BootstrapPeers) == 0 && len(profile.StaticPeers) == 0 {
+		slogs.Logr.Warn("No peers found for the network, so none will be exported", "network", networkName)
+		return definition, nil
+	}
+	definition.NetworkProfiles = map[string]networkProfile{
+		networkName: *profile,
+	}
+
+	return definition, nil
+}
+
+func init() {
+	exportCmd.PersistentFlags().Bool("with-peers", false, "Include the introducer and peers used for the network")
+	exportCmd.PersistentFlags().Bool("as-json", false, "Output as JSON instead of yaml")
+	exportCmd.PersistentFlags().StringP("output", "o", "", "Write the network definition to a file instead of stdout")
+
+	cobra.CheckErr(viper.BindPFlag("net-export-with-peers", exportCmd.PersistentFlags().Lookup("with-peers")))
+	cobra.CheckErr(viper.BindPFlag("net-export-as-json", exportCmd.PersistentFlags().Lookup("as-json")))
+	cobra.CheckErr(viper.BindPFlag("net-export-output", exportCmd.PersistentFlags().Lookup("output")))
+
+	networkCmd.AddCommand(exportCmd)
+}
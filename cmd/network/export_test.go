@@ -0,0 +1,89 @@
+package network
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/chia-network/chia-tools/cmd"
+)
+
+func TestExportNetwork(t *testing.T) {
+	chiaRoot := t.TempDir()
+	mainnet := "mainnet"
+	cfg := &config.ChiaConfig{
+		SelectedNetwork:  &mainnet,
+		NetworkOverrides: testNetworkOverrides(),
+	}
+	cfg.NetworkOverrides.Constants["othernet"] = config.NetworkConstants{}
+	cfg.NetworkOverrides.Config["othernet"] = config.NetworkConfig{}
+
+	definition, err := exportNetwork(chiaRoot, cfg, "examplenet", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"examplenet"}, definition.networkNames())
+	assert.Nil(t, definition.NetworkProfiles)
+
+	_, err = exportNetwork(chiaRoot, cfg, "missingnet", false)
+	assert.Error(t, err)
+
+	// The output is accepted by import unchanged
+	marshalledYAML, err := yaml.Marshal(definition)
+	assert.NoError(t, err)
+	marshalledJSON, err := json.Marshal(definition)
+	assert.NoError(t, err)
+	for _, data := range [][]byte{marshalledYAML, marshalledJSON} {
+		imported, err := parseNetworkDefinition(data)
+		assert.NoError(t, err)
+		assert.Equal(t, definition.NetworkOverrides.Config, imported.NetworkOverrides.Config)
+		assert.Equal(t, []string{"examplenet"}, imported.networkNames())
+	}
+}
+
+func TestExportNetwork_WithPeers(t *testing.T) {
+	cmd.InitLogs()
+	chiaRoot := t.TempDir()
+	mainnet := "mainnet"
+	cfg := &config.ChiaConfig{
+		SelectedNetwork:  &mainnet,
+		NetworkOverrides: testNetworkOverrides(),
+	}
+
+	// No retained settings, so there are no peers to export
+	definition, err := exportNetwork(chiaRoot, cfg, "examplenet", true)
+	assert.NoError(t, err)
+	assert.Nil(t, definition.NetworkProfiles)
+
+	// Settings retained in the legacy format are migrated when read
+	settings, err := json.Marshal(legacyRetainedSettings{
+		DNSServers:     []string{"dns-introducer.example.com"},
+		BootstrapPeers: []string{"node.example.com"},
+		FullNodePeers:  []config.Peer{{Host: "localhost", Port: 58445}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(path.Join(chiaRoot, "db", "examplenet"), 0755))
+	assert.NoError(t, os.WriteFile(path.Join(chiaRoot, "db", "examplenet", "settings.json"), settings, 0644))
+
+	definition, err = exportNetwork(chiaRoot, cfg, "examplenet", true)
+	assert.NoError(t, err)
+	assert.Equal(t, networkProfile{
+		DNSIntroducers: []string{"dns-introducer.example.com"},
+		BootstrapPeers: []string{"node.example.com"},
+	}, definition.NetworkProfiles["examplenet"])
+
+	// When the network is selected, the peers come from the current config
+	examplenet := "examplenet"
+	cfg.SelectedNetwork = &examplenet
+	cfg.FullNode.IntroducerPeer.Host = "introducer.example.com"
+	cfg.Seeder.StaticPeers = []string{"static.example.com"}
+	definition, err = exportNetwork(chiaRoot, cfg, "examplenet", true)
+	assert.NoError(t, err)
+	assert.Equal(t, networkProfile{
+		Introducer:  "introducer.example.com",
+		StaticPeers: []string{"static.example.com"},
+	}, definition.NetworkProfiles["examplenet"])
+}
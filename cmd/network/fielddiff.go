@@ -0,0 +1,80 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// fieldDifference is a single field that differs between two network definitions
+type fieldDifference struct {
+	Field string `json:"field"`
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+}
+
+// diffFields compares two structs of the same type field by field, naming each field by its yaml key.
+// Unset pointer fields are reported as nil.
+func diffFields(prefix string, a, b any) []fieldDifference {
+	aValue := reflect.Indirect(reflect.ValueOf(a))
+	bValue := reflect.Indirect(reflect.ValueOf(b))
+	structType := aValue.Type()
+
+	var differences []fieldDifference
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+		if prefix != "" {
+			name = fmt.Sprintf("%s.%s", prefix, name)
+		}
+
+		aField := fieldValue(aValue.Field(i))
+		bField := fieldValue(bValue.Field(i))
+		if !reflect.DeepEqual(aField, bField) {
+			differences = append(differences, fieldDifference{
+				Field: name,
+				A:     aField,
+				B:     bField,
+			})
+		}
+	}
+
+	return differences
+}
+
+// fieldValue dereferences pointer fields so set pointers compare by value, and unset pointers are nil
+func fieldValue(value reflect.Value) any {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	return value.Interface()
+}
+
+// printFieldDiffs writes the differences as a table, with a column for each side of the comparison
+func printFieldDiffs(w io.Writer, differences []fieldDifference, labelA, labelB string) {
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Field\t%s\t%s\n", labelA, labelB)
+	for _, difference := range differences {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", difference.Field, diffValue(difference.A), diffValue(difference.B))
+	}
+	_ = tw.Flush()
+}
+
+func diffValue(value any) string {
+	if value == nil {
+		return "<unset>"
+	}
+	return fmt.Sprintf("%v", value)
+}
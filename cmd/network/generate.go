@@ -1,11 +1,16 @@
 package network
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/chia-network/go-chia-libs/pkg/bech32m"
 	"github.com/chia-network/go-chia-libs/pkg/config"
 	"github.com/chia-network/go-chia-libs/pkg/ptr"
 	"github.com/chia-network/go-chia-libs/pkg/types"
@@ -18,55 +23,78 @@ import (
 
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
-	Use:     "generate",
-	Short:   "Generates new network constants",
-	Example: "chia-tools network generate --network examplenet",
+	Use:   "generate",
+	Short: "Generates new network constants",
+	Example: `chia-tools network generate --network examplenet
+
+# Write a config and certificates for each node role of a new testnet, ready to use as CHIA_ROOT
+chia-tools network generate --network examplenet --bundle ./examplenet
+
+# Use a random genesis challenge. The seed is recorded in the output so the network can be generated again
+chia-tools network generate --network examplenet --random-genesis --with-constants
+
+# Generate a network from a spec file, overriding one of its values
+chia-tools network generate --spec examplenet.yaml --min-plot-size 20`,
 	Run: func(cmd *cobra.Command, args []string) {
-		networkName := viper.GetString("tn-gen-network")
-		genesisHashBytes := sha256.Sum256([]byte(networkName))
-		genesisHash := hex.EncodeToString(genesisHashBytes[:32])
+		if viper.GetString("tn-gen-bundle") == "" {
+			// Keep stdout clean so the output can be piped straight into network import or validate
+			slogs.Init(viper.GetString("log-level"), slogs.WithWriter(os.Stderr))
+		}
 
 		constants := &config.NetworkConstants{
-			AggSigMeAdditionalData:         genesisHash,
-			DifficultyConstantFactor:       types.Uint128From64(viper.GetUint64("tn-gen-diff-constant-factor")),
-			DifficultyStarting:             viper.GetUint64("tn-gen-difficulty-starting"),
-			EpochBlocks:                    viper.GetUint32("tn-gen-epoch-blocks"),
-			GenesisChallenge:               genesisHash,
-			GenesisPreFarmPoolPuzzleHash:   viper.GetString("tn-gen-pre-farm-pool-puz-hash"),
-			GenesisPreFarmFarmerPuzzleHash: viper.GetString("tn-gen-pre-farm-farmer-puz-hash"),
-			MempoolBlockBuffer:             cast.ToUint8(viper.Get("tn-gen-mempool-block-buffer")),
-			MinPlotSize:                    cast.ToUint8(viper.Get("tn-gen-min-plot-size")),
-			NetworkType:                    1,
-			SubSlotItersStarting:           viper.GetUint64("tn-gen-sub-slot-iters-starting"),
-			HardForkHeight:                 ptr.Uint32Ptr(0),
+			NetworkType:    1,
+			HardForkHeight: ptr.Uint32Ptr(0),
 		}
-		if viper.IsSet("tn-gen-hard-fork2-height") {
-			constants.HardFork2Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-hard-fork2-height"))
+		cfg := &config.NetworkConfig{}
+		applyGenerateFlags(constants, cfg, false)
+
+		// Values from a spec replace the flag defaults, but any flags that were provided still take precedence
+		networkName := viper.GetString("tn-gen-network")
+		var specSeed string
+		if specFile := viper.GetString("tn-gen-spec"); specFile != "" {
+			spec, err := loadGenerateSpec(specFile, constants, cfg)
+			if err != nil {
+				slogs.Logr.Fatal("error loading network spec", "error", err)
+			}
+			if !viper.IsSet("tn-gen-network") && spec.Network != "" {
+				networkName = spec.Network
+			}
+			specSeed = spec.GenesisSeed
+			applyGenerateFlags(constants, cfg, true)
 		}
-		if viper.IsSet("tn-gen-number-zero-bits-plot-filter-v2") {
-			value := cast.ToUint8(viper.Get("tn-gen-number-zero-bits-plot-filter-v2"))
-			constants.NumberZeroBitsPlotFilterV2 = &value
+		if networkName == "" {
+			slogs.Logr.Fatal("--network is required unless the spec provides a network name")
 		}
-		if viper.IsSet("tn-gen-plot-v1-phase-out-epoch-bits") {
-			value := cast.ToUint8(viper.Get("tn-gen-plot-v1-phase-out-epoch-bits"))
-			constants.PlotV1PhaseOutEpochBits = &value
+
+		seed := viper.GetString("tn-gen-genesis-seed")
+		challenge := viper.GetString("tn-gen-genesis-challenge")
+		randomGenesis := viper.GetBool("tn-gen-random-genesis")
+		if seed == "" && challenge == "" && !randomGenesis {
+			// Fall back to any genesis seed or challenge from the spec
+			seed = specSeed
+			challenge = constants.GenesisChallenge
 		}
-		if viper.IsSet("tn-gen-plot-filter-v2-first-adjustment-height") {
-			constants.PlotFilterV2FirstAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-first-adjustment-height"))
+		genesisHash, genesisSeed, err := genesisChallenge(networkName, seed, challenge, randomGenesis)
+		if err != nil {
+			slogs.Logr.Fatal("error determining genesis challenge", "error", err)
 		}
-		if viper.IsSet("tn-gen-plot-filter-v2-second-adjustment-height") {
-			constants.PlotFilterV2SecondAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-second-adjustment-height"))
+		constants.GenesisChallenge = genesisHash
+		constants.AggSigMeAdditionalData = genesisHash
+
+		if cfg.AddressPrefix == "" {
+			slogs.Logr.Fatal("--address-prefix must not be empty")
 		}
-		if viper.IsSet("tn-gen-plot-filter-v2-third-adjustment-height") {
-			constants.PlotFilterV2ThirdAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-third-adjustment-height"))
+		constants.GenesisPreFarmPoolPuzzleHash, err = puzzleHash(constants.GenesisPreFarmPoolPuzzleHash, cfg.AddressPrefix)
+		if err != nil {
+			slogs.Logr.Fatal("invalid pre-farm pool puzzle hash", "error", err)
 		}
-		if viper.IsSet("tn-gen-soft-fork-8-9-height") {
-			constants.SoftFork8Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-soft-fork-8-9-height"))
-			constants.SoftFork9Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-soft-fork-8-9-height"))
+		constants.GenesisPreFarmFarmerPuzzleHash, err = puzzleHash(constants.GenesisPreFarmFarmerPuzzleHash, cfg.AddressPrefix)
+		if err != nil {
+			slogs.Logr.Fatal("invalid pre-farm farmer puzzle hash", "error", err)
 		}
-		cfg := &config.NetworkConfig{
-			AddressPrefix:       "txch",
-			DefaultFullNodePort: viper.GetUint16("tn-gen-port"),
+
+		if !viper.GetBool("tn-gen-skip-validation") && !logValidationErrors(networkName, validateGeneratedConstants(*constants)) {
+			slogs.Logr.Fatal("Generated network constants failed validation. Use --skip-validation to output them anyway", "network", networkName)
 		}
 
 		netOverrides := &config.NetworkOverrides{
@@ -78,15 +106,25 @@ var generateCmd = &cobra.Command{
 			},
 		}
 
+		bundleDir := viper.GetString("tn-gen-bundle")
+
+		// The bundle always includes the full network definition, so it can be imported by network import
 		var toMarshal any
-		if viper.GetBool("tn-gen-with-constants") {
-			toMarshal = netOverrides
+		if viper.GetBool("tn-gen-with-constants") || bundleDir != "" {
+			toMarshal = generatedNetwork{
+				GenesisSeed:      genesisSeed,
+				NetworkOverrides: *netOverrides,
+			}
 		} else {
 			toMarshal = constants
 		}
 
+		// The bare constants have nowhere to record the seed, so it is logged instead
+		if _, bare := toMarshal.(*config.NetworkConstants); bare && genesisSeed != "" {
+			slogs.Logr.Info("Derived GENESIS_CHALLENGE from a genesis seed. Use --genesis-seed or --with-constants to generate this network again", "genesis_seed", genesisSeed)
+		}
+
 		var marshalled []byte
-		var err error
 		if viper.GetBool("tn-gen-as-json") {
 			marshalled, err = json.Marshal(toMarshal)
 		} else {
@@ -96,21 +134,187 @@ var generateCmd = &cobra.Command{
 		if err != nil {
 			slogs.Logr.Fatal("error marshalling", "error", err)
 		}
+
+		if bundleDir != "" {
+			definitionFile := "network.yaml"
+			if viper.GetBool("tn-gen-as-json") {
+				definitionFile = "network.json"
+			}
+			hosts := bundleHosts{
+				FullNode:   viper.GetString("tn-gen-full-node-host"),
+				Introducer: viper.GetString("tn-gen-introducer-host"),
+				Seeder:     viper.GetString("tn-gen-seeder-host"),
+			}
+			err = writeNetworkBundle(bundleDir, networkName, *constants, *cfg, hosts, marshalled, definitionFile)
+			if err != nil {
+				slogs.Logr.Fatal("error writing network bundle", "error", err)
+			}
+			slogs.Logr.Info("Wrote network bundle", "network", networkName, "path", bundleDir)
+			return
+		}
+
 		fmt.Print(string(marshalled))
 	},
 }
 
+// applyGenerateFlags sets the constants and config from the generate flags. Flags for optional constants are only
+// applied when they are set. With onlySet, the remaining flags are also only applied when they are set, so they
+// can override values loaded from a spec.
+func applyGenerateFlags(constants *config.NetworkConstants, cfg *config.NetworkConfig, onlySet bool) {
+	apply := func(key string) bool {
+		return !onlySet || viper.IsSet(key)
+	}
+
+	if apply("tn-gen-diff-constant-factor") {
+		constants.DifficultyConstantFactor = types.Uint128From64(viper.GetUint64("tn-gen-diff-constant-factor"))
+	}
+	if apply("tn-gen-difficulty-starting") {
+		constants.DifficultyStarting = viper.GetUint64("tn-gen-difficulty-starting")
+	}
+	if apply("tn-gen-epoch-blocks") {
+		constants.EpochBlocks = viper.GetUint32("tn-gen-epoch-blocks")
+	}
+	if apply("tn-gen-pre-farm-pool-puz-hash") {
+		constants.GenesisPreFarmPoolPuzzleHash = viper.GetString("tn-gen-pre-farm-pool-puz-hash")
+	}
+	if apply("tn-gen-pre-farm-farmer-puz-hash") {
+		constants.GenesisPreFarmFarmerPuzzleHash = viper.GetString("tn-gen-pre-farm-farmer-puz-hash")
+	}
+	if apply("tn-gen-mempool-block-buffer") {
+		constants.MempoolBlockBuffer = cast.ToUint8(viper.Get("tn-gen-mempool-block-buffer"))
+	}
+	if apply("tn-gen-min-plot-size") {
+		constants.MinPlotSize = cast.ToUint8(viper.Get("tn-gen-min-plot-size"))
+	}
+	if apply("tn-gen-sub-slot-iters-starting") {
+		constants.SubSlotItersStarting = viper.GetUint64("tn-gen-sub-slot-iters-starting")
+	}
+	if apply("tn-gen-address-prefix") {
+		cfg.AddressPrefix = viper.GetString("tn-gen-address-prefix")
+	}
+	if apply("tn-gen-port") {
+		cfg.DefaultFullNodePort = viper.GetUint16("tn-gen-port")
+	}
+
+	if viper.IsSet("tn-gen-hard-fork2-height") {
+		constants.HardFork2Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-hard-fork2-height"))
+	}
+	if viper.IsSet("tn-gen-number-zero-bits-plot-filter-v2") {
+		value := cast.ToUint8(viper.Get("tn-gen-number-zero-bits-plot-filter-v2"))
+		constants.NumberZeroBitsPlotFilterV2 = &value
+	}
+	if viper.IsSet("tn-gen-plot-v1-phase-out-epoch-bits") {
+		value := cast.ToUint8(viper.Get("tn-gen-plot-v1-phase-out-epoch-bits"))
+		constants.PlotV1PhaseOutEpochBits = &value
+	}
+	if viper.IsSet("tn-gen-plot-filter-v2-first-adjustment-height") {
+		constants.PlotFilterV2FirstAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-first-adjustment-height"))
+	}
+	if viper.IsSet("tn-gen-plot-filter-v2-second-adjustment-height") {
+		constants.PlotFilterV2SecondAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-second-adjustment-height"))
+	}
+	if viper.IsSet("tn-gen-plot-filter-v2-third-adjustment-height") {
+		constants.PlotFilterV2ThirdAdjustmentHeight = ptr.Uint32Ptr(viper.GetUint32("tn-gen-plot-filter-v2-third-adjustment-height"))
+	}
+	if viper.IsSet("tn-gen-soft-fork-8-9-height") {
+		constants.SoftFork8Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-soft-fork-8-9-height"))
+		constants.SoftFork9Height = ptr.Uint32Ptr(viper.GetUint32("tn-gen-soft-fork-8-9-height"))
+	}
+}
+
+// generatedNetwork is the network definition output with --with-constants. It records the seed the genesis challenge
+// was derived from, so the network can be generated again exactly.
+type generatedNetwork struct {
+	GenesisSeed             string `yaml:"genesis_seed,omitempty" json:"genesis_seed,omitempty"`
+	config.NetworkOverrides `yaml:",inline"`
+}
+
+// genesisChallenge returns the genesis challenge for a new network, and the seed it was derived from.
+// The challenge is the sha256 of the seed, which defaults to the network name. The returned seed is only set when a
+// seed was provided or generated, not for the network name or a challenge provided directly. A seed may be provided
+// with the challenge it derives, as in the output of --with-constants.
+func genesisChallenge(networkName, seed, challenge string, random bool) (string, string, error) {
+	if challenge != "" {
+		challenge = strings.ToLower(strings.TrimPrefix(challenge, "0x"))
+		if err := validateBytes32(challenge); err != nil {
+			return "", "", fmt.Errorf("genesis challenge %w", err)
+		}
+	}
+	if seed != "" && challenge != "" && !random && seedChallenge(seed) == challenge {
+		return challenge, seed, nil
+	}
+
+	options := 0
+	for _, set := range []bool{seed != "", challenge != "", random} {
+		if set {
+			options++
+		}
+	}
+	if options > 1 {
+		return "", "", errors.New("only one of a genesis seed, a genesis challenge, or a random genesis may be provided, unless the genesis challenge is derived from the seed")
+	}
+
+	if challenge != "" {
+		return challenge, "", nil
+	}
+
+	if random {
+		randomBytes := make([]byte, 32)
+		_, err := rand.Read(randomBytes)
+		if err != nil {
+			return "", "", fmt.Errorf("error generating random genesis seed: %w", err)
+		}
+		seed = hex.EncodeToString(randomBytes)
+	}
+	if seed == "" {
+		return seedChallenge(networkName), "", nil
+	}
+
+	return seedChallenge(seed), seed, nil
+}
+
+// seedChallenge derives a genesis challenge from a seed
+func seedChallenge(seed string) string {
+	genesisHashBytes := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(genesisHashBytes[:])
+}
+
+// puzzleHash accepts either a 32 byte hex puzzle hash, or a bech32m address using the network's address prefix,
+// and returns the puzzle hash as hex
+func puzzleHash(value, addressPrefix string) (string, error) {
+	value = strings.TrimSpace(value)
+	if validateBytes32(value) == nil {
+		return strings.ToLower(strings.TrimPrefix(value, "0x")), nil
+	}
+
+	prefix, decoded, err := bech32m.DecodePuzzleHash(value)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither a 32 byte hex puzzle hash nor a valid address: %w", value, err)
+	}
+	if prefix != addressPrefix {
+		return "", fmt.Errorf("address %s has prefix %s, but the network's address prefix is %s", value, prefix, addressPrefix)
+	}
+
+	return hex.EncodeToString(decoded[:]), nil
+}
+
 func init() {
 	generateCmd.PersistentFlags().String("network", "", "Name of the network to create")
+	generateCmd.PersistentFlags().String("spec", "", "YAML or JSON file defining the network constants and config. Flags override values in the spec")
+	// Genesis options. The genesis challenge is also used as AGG_SIG_ME_ADDITIONAL_DATA
+	generateCmd.PersistentFlags().String("genesis-seed", "", "Seed to derive GENESIS_CHALLENGE from (default is the network name)")
+	generateCmd.PersistentFlags().String("genesis-challenge", "", "Use this 32 byte hex value as GENESIS_CHALLENGE instead of deriving it from a seed")
+	generateCmd.PersistentFlags().Bool("random-genesis", false, "Derive GENESIS_CHALLENGE from a random seed")
 	generateCmd.PersistentFlags().Uint64("diff-constant-factor", uint64(10052721566054), "Specify the value for DIFFICULTY_CONSTANT_FACTOR (Up to uint64max)")
-	generateCmd.PersistentFlags().String("pre-farm-farmer-puz-hash", "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a", "Specify the value for GENESIS_PRE_FARM_FARMER_PUZZLE_HASH")
-	generateCmd.PersistentFlags().String("pre-farm-pool-puz-hash", "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a", "Specify the value for GENESIS_PRE_FARM_POOL_PUZZLE_HASH")
+	generateCmd.PersistentFlags().String("pre-farm-farmer-puz-hash", "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a", "Specify the value for GENESIS_PRE_FARM_FARMER_PUZZLE_HASH, as hex or an address")
+	generateCmd.PersistentFlags().String("pre-farm-pool-puz-hash", "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a", "Specify the value for GENESIS_PRE_FARM_POOL_PUZZLE_HASH, as hex or an address")
 	generateCmd.PersistentFlags().Uint8("min-plot-size", uint8(18), "Specify the minimum plot size MIN_PLOT_SIZE")
 	generateCmd.PersistentFlags().Uint8("mempool-block-buffer", uint8(10), "Specify MEMPOOL_BLOCK_BUFFER")
 	generateCmd.PersistentFlags().Uint32("epoch-blocks", uint32(768), "specify EPOCH_BLOCKS")
 	generateCmd.PersistentFlags().Uint64("difficulty-starting", uint64(250), "Specify starting difficulty")
 	generateCmd.PersistentFlags().Uint64("sub-slot-iters-starting", uint64(1<<25), "Specify starting sub slot iters")
 	generateCmd.PersistentFlags().Uint16("port", uint16(58445), "Specify the port the network full nodes should use")
+	generateCmd.PersistentFlags().String("address-prefix", "txch", "Specify the address prefix for the network")
 	// New configuration options to support testing hard fork 2
 	generateCmd.PersistentFlags().Uint32("hard-fork2-height", uint32(0), "Block height when the 3.0 hard fork will activate")
 	generateCmd.PersistentFlags().Uint8("number-zero-bits-plot-filter-v2", uint8(0), "Number of leading zeroes required to pass plot ID filter (post hard fork only)")
@@ -123,8 +327,18 @@ func init() {
 	// Output format options
 	generateCmd.PersistentFlags().Bool("as-json", false, "Output as JSON blob instead of yaml")
 	generateCmd.PersistentFlags().Bool("with-constants", false, "Include constants and default ports")
+	generateCmd.PersistentFlags().Bool("skip-validation", false, "Output the constants even if they fail validation")
+	// Bundle options
+	generateCmd.PersistentFlags().String("bundle", "", "Write a directory with a CHIA_ROOT for each node role, sharing a private CA, instead of printing the constants")
+	generateCmd.PersistentFlags().String("full-node-host", "full-node", "Host the other nodes in the bundle use to reach the full node")
+	generateCmd.PersistentFlags().String("introducer-host", "introducer", "Host the nodes in the bundle use to reach the introducer")
+	generateCmd.PersistentFlags().String("seeder-host", "seeder", "Host the nodes in the bundle use as their DNS introducer")
 
 	cobra.CheckErr(viper.BindPFlag("tn-gen-network", generateCmd.PersistentFlags().Lookup("network")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-spec", generateCmd.PersistentFlags().Lookup("spec")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-genesis-seed", generateCmd.PersistentFlags().Lookup("genesis-seed")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-genesis-challenge", generateCmd.PersistentFlags().Lookup("genesis-challenge")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-random-genesis", generateCmd.PersistentFlags().Lookup("random-genesis")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-diff-constant-factor", generateCmd.PersistentFlags().Lookup("diff-constant-factor")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-pre-farm-farmer-puz-hash", generateCmd.PersistentFlags().Lookup("pre-farm-farmer-puz-hash")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-pre-farm-pool-puz-hash", generateCmd.PersistentFlags().Lookup("pre-farm-pool-puz-hash")))
@@ -134,6 +348,7 @@ func init() {
 	cobra.CheckErr(viper.BindPFlag("tn-gen-difficulty-starting", generateCmd.PersistentFlags().Lookup("difficulty-starting")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-sub-slot-iters-starting", generateCmd.PersistentFlags().Lookup("sub-slot-iters-starting")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-port", generateCmd.PersistentFlags().Lookup("port")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-address-prefix", generateCmd.PersistentFlags().Lookup("address-prefix")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-hard-fork2-height", generateCmd.PersistentFlags().Lookup("hard-fork2-height")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-number-zero-bits-plot-filter-v2", generateCmd.PersistentFlags().Lookup("number-zero-bits-plot-filter-v2")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-plot-v1-phase-out-epoch-bits", generateCmd.PersistentFlags().Lookup("plot-v1-phase-out-epoch-bits")))
@@ -143,6 +358,11 @@ func init() {
 	cobra.CheckErr(viper.BindPFlag("tn-gen-soft-fork-8-9-height", generateCmd.PersistentFlags().Lookup("soft-fork-8-9-height")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-as-json", generateCmd.PersistentFlags().Lookup("as-json")))
 	cobra.CheckErr(viper.BindPFlag("tn-gen-with-constants", generateCmd.PersistentFlags().Lookup("with-constants")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-skip-validation", generateCmd.PersistentFlags().Lookup("skip-validation")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-bundle", generateCmd.PersistentFlags().Lookup("bundle")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-full-node-host", generateCmd.PersistentFlags().Lookup("full-node-host")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-introducer-host", generateCmd.PersistentFlags().Lookup("introducer-host")))
+	cobra.CheckErr(viper.BindPFlag("tn-gen-seeder-host", generateCmd.PersistentFlags().Lookup("seeder-host")))
 
 	networkCmd.AddCommand(generateCmd)
 }
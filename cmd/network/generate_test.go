@@ -0,0 +1,105 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenesisChallenge(t *testing.T) {
+	// The default is unchanged from deriving the challenge from the network name, and no seed is recorded for it
+	challenge, seed, err := genesisChallenge("examplenet", "", "", false)
+	assert.NoError(t, err)
+	assert.Empty(t, seed)
+	assert.Equal(t, seedChallenge("examplenet"), challenge)
+	fromName := challenge
+
+	challenge, seed, err = genesisChallenge("examplenet", "team-a", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a", seed)
+	assert.NotEqual(t, fromName, challenge)
+	assert.NoError(t, validateBytes32(challenge))
+
+	// The same seed always produces the same challenge
+	again, _, err := genesisChallenge("othernet", "team-a", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, challenge, again)
+
+	explicit := "0xE3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"
+	challenge, seed, err = genesisChallenge("examplenet", "", explicit, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", challenge)
+	assert.Empty(t, seed)
+
+	_, _, err = genesisChallenge("examplenet", "", "abcd", false)
+	assert.Error(t, err)
+
+	random1, seed1, err := genesisChallenge("examplenet", "", "", true)
+	assert.NoError(t, err)
+	random2, seed2, err := genesisChallenge("examplenet", "", "", true)
+	assert.NoError(t, err)
+	assert.NotEqual(t, seed1, seed2)
+	assert.NotEqual(t, random1, random2)
+
+	// The recorded random seed reproduces the challenge
+	reproduced, _, err := genesisChallenge("examplenet", seed1, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, random1, reproduced)
+
+	_, _, err = genesisChallenge("examplenet", "team-a", "", true)
+	assert.Error(t, err)
+
+	// A seed may be provided with the challenge it derives, but not with any other challenge
+	challenge, seed, err = genesisChallenge("examplenet", "team-a", "0x"+seedChallenge("team-a"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, seedChallenge("team-a"), challenge)
+	assert.Equal(t, "team-a", seed)
+	_, _, err = genesisChallenge("examplenet", "team-a", explicit, false)
+	assert.Error(t, err)
+}
+
+func TestGeneratedNetworkIsImportable(t *testing.T) {
+	generated := generatedNetwork{
+		GenesisSeed:      "team-a",
+		NetworkOverrides: *testNetworkOverrides(),
+	}
+
+	marshalledYAML, err := yaml.Marshal(generated)
+	assert.NoError(t, err)
+	marshalledJSON, err := json.Marshal(generated)
+	assert.NoError(t, err)
+
+	for _, data := range [][]byte{marshalledYAML, marshalledJSON} {
+		assert.Contains(t, string(data), "genesis_seed")
+		definition, err := parseNetworkDefinition(data)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"examplenet"}, definition.networkNames())
+	}
+}
+
+func TestPuzzleHash(t *testing.T) {
+	expected := "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a"
+
+	for _, value := range []string{
+		expected,
+		"0x08296FC227DECD043AEE855741444538E4CC9A31772C4D1A9E6242D1E777E42A",
+		"txch1pq5kls38mmxsgwhws4t5z3z98rjvex33wuky6x57vfpdremhus4q9fxp5j",
+	} {
+		actual, err := puzzleHash(value, "txch")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// Bad checksum
+	_, err := puzzleHash("txch1pq5kls38mmxsgwhws4t5z3z98rjvex33wuky6x57vfpdremhus4q9fxp5k", "txch")
+	assert.Error(t, err)
+
+	// Valid address for a different network
+	_, err = puzzleHash("xch1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqm6ks6e8mvy", "txch")
+	assert.ErrorContains(t, err, "address prefix is txch")
+
+	_, err = puzzleHash("not a puzzle hash", "txch")
+	assert.Error(t, err)
+}
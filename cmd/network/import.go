@@ -1,74 +1,136 @@
 package network
 
 import (
-	"io"
-	"net/http"
+	"os"
+	"path"
 
 	"github.com/chia-network/go-chia-libs/pkg/config"
 	"github.com/chia-network/go-modules/pkg/slogs"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
 )
 
 // importCmd represents the import command
 var importCmd = &cobra.Command{
 	Use:   "import",
-	Short: "Import a network configuration from a remote source",
+	Short: "Import a network configuration from a remote source, file, or stdin",
 	Example: `chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml
 
+# Import from a local YAML or JSON file. Either a full chia config, or the output of network generate --with-constants
+chia-tools network import --network mytestnet --file my-network-config.yml
+
+# Generate and import a network in one step. --network may be omitted when the input defines a single network
+chia-tools network generate --network mytestnet --with-constants | chia-tools network import --file -
+
 # Show what changes would be made without actually importing
-chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml --dry-run`,
-	Run: func(cmd *cobra.Command, args []string) {
+chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml --dry-run
+
+# Replace a network that already exists with a different definition
+chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml --force
+
+# Verify the checksum of the remote config before importing
+chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml --sha256 <hex digest>
+
+# Verify a detached ed25519 signature (defaults to the url with .sig appended) against a pinned public key.
+# Public keys can also be pinned in .chia-tools.yaml under net-import-public-keys, which makes a signature required.
+chia-tools network import --network mytestnet --url https://example.com/my-network-config.yml --public-key <hex or base64 key>`,
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
 		network := viper.GetString("net-import-network")
 		url := viper.GetString("net-import-url")
+		file := viper.GetString("net-import-file")
 		dryRun := viper.GetBool("dry-run")
 
+		if (url == "") == (file == "") {
+			slogs.Logr.Fatal("Exactly one of --url or --file must be provided")
+		}
+		source := url
+		if file != "" {
+			source = file
+		}
+
 		if dryRun {
-			slogs.Logr.Info("DRY RUN: Would import network settings", "network", network, "url", url)
+			slogs.Logr.Info("DRY RUN: Would import network settings", "network", network, "source", source)
 		} else {
-			slogs.Logr.Info("Importing remote network settings", "network", network, "url", url)
+			slogs.Logr.Info("Importing network settings", "network", network, "source", source)
 		}
 
-		resp, err := http.Get(url)
+		insecure := viper.GetBool("net-import-insecure")
+		cfgBytes, err := readSource(url, file, insecure)
 		if err != nil {
-			slogs.Logr.Fatal("Failed to load remote network settings", "error", err)
+			slogs.Logr.Fatal("Failed to load network settings", "error", err)
 		}
-		defer func(Body io.ReadCloser) {
-			err := Body.Close()
+
+		if expectedSum := viper.GetString("net-import-sha256"); expectedSum != "" {
+			err = verifySHA256(cfgBytes, expectedSum)
 			if err != nil {
-				slogs.Logr.Fatal("Failed to close remote network settings body", "error", err)
+				slogs.Logr.Fatal("Network settings failed checksum verification", "error", err)
 			}
-		}(resp.Body)
+			slogs.Logr.Info("Verified sha256 checksum of network settings")
+		}
 
-		cfgBytes, err := io.ReadAll(resp.Body)
+		// When public keys are pinned, a valid signature is required before the network definition is accepted
+		publicKeys := viper.GetStringSlice("net-import-public-keys")
+		signatureURL := viper.GetString("net-import-signature-url")
+		signatureFile := viper.GetString("net-import-signature-file")
+		if len(publicKeys) > 0 || signatureURL != "" || signatureFile != "" {
+			if signatureURL == "" && signatureFile == "" {
+				switch file {
+				case "":
+					signatureURL = url + ".sig"
+				case "-":
+					slogs.Logr.Fatal("A signature must be provided with --signature-file or --signature-url when reading from stdin")
+				default:
+					signatureFile = file + ".sig"
+				}
+			}
+			signature, err := readSource(signatureURL, signatureFile, insecure)
+			if err != nil {
+				slogs.Logr.Fatal("Failed to load signature for network settings", "error", err)
+			}
+			err = verifySignature(cfgBytes, signature, publicKeys)
+			if err != nil {
+				slogs.Logr.Fatal("Network settings failed signature verification", "error", err)
+			}
+			slogs.Logr.Info("Verified signature of network settings")
+		}
+
+		definition, err := parseNetworkDefinition(cfgBytes)
 		if err != nil {
-			slogs.Logr.Fatal("Failed to read remote network settings body", "error", err)
+			slogs.Logr.Fatal("Failed to parse network settings", "error", err)
 		}
 
-		cfg := &config.ChiaConfig{}
-		err = yaml.Unmarshal(cfgBytes, cfg)
+		if network == "" {
+			names := definition.networkNames()
+			if len(names) != 1 {
+				slogs.Logr.Fatal("--network is required unless the network settings define exactly one network", "networks", names)
+			}
+			network = names[0]
+			slogs.Logr.Info("Discovered network in network settings", "network", network)
+		}
+		err = validateNetworkName(network)
 		if err != nil {
-			slogs.Logr.Fatal("Failed to unmarshal remote network settings body", "error", err)
+			slogs.Logr.Fatal("Invalid network name", "error", err)
 		}
 
-		if _, ok := cfg.NetworkOverrides.Constants[network]; !ok {
-			slogs.Logr.Fatal("Network constants not found in remote config", "network", network)
+		constants, ok := definition.NetworkOverrides.Constants[network]
+		if !ok {
+			slogs.Logr.Fatal("Network constants not found in network settings", "network", network)
 		}
-		if _, ok := cfg.NetworkOverrides.Config[network]; !ok {
-			slogs.Logr.Fatal("Network config not found in remote config", "network", network)
+		netConfig, ok := definition.NetworkOverrides.Config[network]
+		if !ok {
+			slogs.Logr.Fatal("Network config not found in network settings", "network", network)
 		}
 
-		if dryRun {
-			slogs.Logr.Info("DRY RUN: Would add network constants", "network", network)
-			slogs.Logr.Info("DRY RUN: Would add network config", "network", network)
-			if viper.GetBool("net-import-switch") {
-				slogs.Logr.Info("DRY RUN: Would switch to network", "network", network)
-			}
-			slogs.Logr.Info("DRY RUN: No changes would be made to the config file")
-			return
+		if !viper.GetBool("net-import-skip-validation") && !logValidationErrors(network, validateNetworkConstants(constants)) {
+			slogs.Logr.Fatal("Network constants failed validation. Use --skip-validation to import anyway", "network", network)
 		}
 
+		// The network settings may optionally include a profile with the peers to use for this network
+		profile, hasProfile := definition.NetworkProfiles[network]
+
 		chiaRoot, err := config.GetChiaRootPath()
 		if err != nil {
 			slogs.Logr.Fatal("error determining chia root", "error", err)
@@ -81,33 +143,107 @@ chia-tools network import --network mytestnet --url https://example.com/my-netwo
 		}
 		slogs.Logr.Debug("Successfully loaded config")
 
-		localCfg.NetworkOverrides.Constants[network] = cfg.NetworkOverrides.Constants[network]
-		localCfg.NetworkOverrides.Config[network] = cfg.NetworkOverrides.Config[network]
+		force := viper.GetBool("net-import-force")
+		if checkImportConflicts(localCfg, network, constants, netConfig) && !force && !dryRun {
+			slogs.Logr.Fatal("Network already exists with a different definition. Use --force to overwrite it", "network", network)
+		}
+
+		if dryRun {
+			slogs.Logr.Info("DRY RUN: Would add network constants", "network", network)
+			slogs.Logr.Info("DRY RUN: Would add network config", "network", network)
+			if hasProfile {
+				profileFile, _ := profilePath(chiaRoot, network)
+				slogs.Logr.Info("DRY RUN: Would store network profile", "network", network, "path", profileFile)
+			}
+			if viper.GetBool("net-import-switch") {
+				slogs.Logr.Info("DRY RUN: Would switch to network", "network", network)
+			}
+			slogs.Logr.Info("DRY RUN: No changes would be made to the config file")
+			return
+		}
+
+		setNetwork(localCfg, network, constants, netConfig)
 
-		err = localCfg.Save()
+		err = backups.SaveConfig(localCfg, path.Join(chiaRoot, "config", "config.yaml"), "network import")
 		if err != nil {
 			slogs.Logr.Fatal("Failed to save config", "error", err)
 		}
 
 		slogs.Logr.Info("Successfully imported to config")
 
+		if hasProfile {
+			err = saveNetworkProfile(chiaRoot, network, profile)
+			if err != nil {
+				slogs.Logr.Fatal("Failed to store network profile", "error", err)
+			}
+			profileFile, _ := profilePath(chiaRoot, network)
+			slogs.Logr.Info("Stored network profile", "network", network, "path", profileFile)
+		}
+
 		if viper.GetBool("net-import-switch") {
 			SwitchNetwork(network, true)
 		}
-	},
+	}),
+}
+
+// checkImportConflicts compares the imported definition with any existing definition of the network in the local
+// config, printing the differences. Returns true if the network exists and the definitions differ.
+func checkImportConflicts(localCfg *config.ChiaConfig, network string, constants config.NetworkConstants, netConfig config.NetworkConfig) bool {
+	if localCfg.NetworkOverrides == nil {
+		return false
+	}
+	localConstants, hasConstants := localCfg.NetworkOverrides.Constants[network]
+	localConfig, hasConfig := localCfg.NetworkOverrides.Config[network]
+	if !hasConstants && !hasConfig {
+		return false
+	}
+
+	var differences []fieldDifference
+	if hasConstants {
+		differences = append(differences, diffFields("constants", localConstants, constants)...)
+	}
+	if hasConfig {
+		differences = append(differences, diffFields("config", localConfig, netConfig)...)
+	}
+	if len(differences) == 0 {
+		slogs.Logr.Info("Network already exists in the local config with an identical definition", "network", network)
+		return false
+	}
+
+	slogs.Logr.Warn("Network already exists in the local config with a different definition", "network", network)
+	printFieldDiffs(os.Stdout, differences, "Local", "Imported")
+
+	if hasConstants && localConstants.GenesisChallenge != constants.GenesisChallenge {
+		slogs.Logr.Warn("The imported network has a different GENESIS_CHALLENGE. Any local blockchain database for this network is invalid and must be deleted before syncing", "network", network)
+	}
+
+	return true
 }
 
 func init() {
-	importCmd.PersistentFlags().String("network", "", "Network name to import")
+	importCmd.PersistentFlags().String("network", "", "Network name to import (optional when the config defines a single network)")
 	importCmd.PersistentFlags().StringP("url", "u", "", "URL of the remote config")
+	importCmd.PersistentFlags().StringP("file", "f", "", "Path to a local config to import, or - to read from stdin")
 	importCmd.PersistentFlags().Bool("switch", false, "Whether to immediately switch to the network")
-
-	cobra.CheckErr(importCmd.MarkPersistentFlagRequired("network"))
-	cobra.CheckErr(importCmd.MarkPersistentFlagRequired("url"))
+	importCmd.PersistentFlags().String("sha256", "", "Expected sha256 checksum of the remote config")
+	importCmd.PersistentFlags().String("signature-url", "", "URL of a detached ed25519 signature of the config (default is the url with .sig appended)")
+	importCmd.PersistentFlags().String("signature-file", "", "Path to a detached ed25519 signature of the config (default is the file with .sig appended)")
+	importCmd.PersistentFlags().StringSlice("public-key", nil, "Hex or base64 ed25519 public key trusted to sign network configs. Can be repeated")
+	importCmd.PersistentFlags().Bool("insecure", false, "Allow loading the remote config over plain http")
+	importCmd.PersistentFlags().Bool("force", false, "Overwrite the network if it already exists with a different definition")
+	importCmd.PersistentFlags().Bool("skip-validation", false, "Import the network even if its constants fail validation")
 
 	cobra.CheckErr(viper.BindPFlag("net-import-network", importCmd.PersistentFlags().Lookup("network")))
 	cobra.CheckErr(viper.BindPFlag("net-import-url", importCmd.PersistentFlags().Lookup("url")))
+	cobra.CheckErr(viper.BindPFlag("net-import-file", importCmd.PersistentFlags().Lookup("file")))
 	cobra.CheckErr(viper.BindPFlag("net-import-switch", importCmd.PersistentFlags().Lookup("switch")))
+	cobra.CheckErr(viper.BindPFlag("net-import-sha256", importCmd.PersistentFlags().Lookup("sha256")))
+	cobra.CheckErr(viper.BindPFlag("net-import-signature-url", importCmd.PersistentFlags().Lookup("signature-url")))
+	cobra.CheckErr(viper.BindPFlag("net-import-signature-file", importCmd.PersistentFlags().Lookup("signature-file")))
+	cobra.CheckErr(viper.BindPFlag("net-import-public-keys", importCmd.PersistentFlags().Lookup("public-key")))
+	cobra.CheckErr(viper.BindPFlag("net-import-insecure", importCmd.PersistentFlags().Lookup("insecure")))
+	cobra.CheckErr(viper.BindPFlag("net-import-force", importCmd.PersistentFlags().Lookup("force")))
+	cobra.CheckErr(viper.BindPFlag("net-import-skip-validation", importCmd.PersistentFlags().Lookup("skip-validation")))
 
 	networkCmd.AddCommand(importCmd)
 }
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chia-network/chia-tools/cmd"
+)
+
+func TestCheckImportConflicts(t *testing.T) {
+	cmd.InitLogs()
+	overrides := testNetworkOverrides()
+	localCfg := &config.ChiaConfig{NetworkOverrides: overrides}
+	constants := overrides.Constants["examplenet"]
+	netConfig := overrides.Config["examplenet"]
+
+	// New networks and identical definitions don't conflict
+	assert.False(t, checkImportConflicts(localCfg, "othernet", constants, netConfig))
+	assert.False(t, checkImportConflicts(localCfg, "examplenet", constants, netConfig))
+
+	changedConfig := netConfig
+	changedConfig.DefaultFullNodePort = 1234
+	assert.True(t, checkImportConflicts(localCfg, "examplenet", constants, changedConfig))
+
+	changedConstants := constants
+	changedConstants.HardFork2Height = ptr.Uint32Ptr(100)
+	assert.True(t, checkImportConflicts(localCfg, "examplenet", changedConstants, netConfig))
+}
+
+func TestDiffFields(t *testing.T) {
+	a := config.NetworkConstants{MinPlotSize: 18, HardForkHeight: ptr.Uint32Ptr(0)}
+	b := config.NetworkConstants{MinPlotSize: 20, HardForkHeight: ptr.Uint32Ptr(0), HardFork2Height: ptr.Uint32Ptr(10)}
+
+	differences := map[string]fieldDifference{}
+	for _, difference := range diffFields("constants", a, b) {
+		differences[difference.Field] = difference
+	}
+	assert.Len(t, differences, 2)
+	assert.Equal(t, fieldDifference{Field: "constants.MIN_PLOT_SIZE", A: uint8(18), B: uint8(20)}, differences["constants.MIN_PLOT_SIZE"])
+	assert.Equal(t, fieldDifference{Field: "constants.HARD_FORK2_HEIGHT", A: nil, B: uint32(10)}, differences["constants.HARD_FORK2_HEIGHT"])
+
+	assert.Empty(t, diffFields("", a, a))
+}
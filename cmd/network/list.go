@@ -0,0 +1,194 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/internal/utils"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the networks known to the config and their locally cached state",
+	Example: `chia-tools network list
+
+# Output as JSON
+chia-tools network list --as-json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ListNetworks(os.Stdout, viper.GetBool("net-list-as-json"))
+	},
+}
+
+// networkListEntry is the information shown for each network by `network list`
+type networkListEntry struct {
+	Name                string `json:"name"`
+	Selected            bool   `json:"selected"`
+	HasConstants        bool   `json:"has_constants"`
+	HasConfig           bool   `json:"has_config"`
+	AddressPrefix       string `json:"address_prefix"`
+	DefaultFullNodePort uint16 `json:"default_full_node_port"`
+	GenesisChallenge    string `json:"genesis_challenge"`
+	RetainedSettings    bool   `json:"retained_settings"`
+	CacheFilesSize      int64  `json:"cache_files_size"`
+	DatabaseSize        int64  `json:"database_size"`
+}
+
+// ListNetworks outputs every network in the config's network overrides along with its cached state on disk
+func ListNetworks(w io.Writer, asJSON bool) {
+	chiaRoot, err := config.GetChiaRootPath()
+	if err != nil {
+		slogs.Logr.Fatal("error determining chia root", "error", err)
+	}
+	slogs.Logr.Debug("Chia root discovered", "CHIA_ROOT", chiaRoot)
+
+	cfg, err := config.GetChiaConfig()
+	if err != nil {
+		slogs.Logr.Fatal("error loading config", "error", err)
+	}
+	slogs.Logr.Debug("Successfully loaded config")
+
+	entries := listNetworks(chiaRoot, cfg)
+
+	if asJSON {
+		marshalled, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			slogs.Logr.Fatal("error marshalling network list", "error", err)
+		}
+		_, _ = fmt.Fprintln(w, string(marshalled))
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Network\tSelected\tPrefix\tPort\tGenesis Challenge\tRetained Settings\tCache Files\tDatabase")
+	for _, entry := range entries {
+		selected := ""
+		if entry.Selected {
+			selected = "*"
+		}
+		prefix, port := "-", "-"
+		if entry.HasConfig {
+			prefix = entry.AddressPrefix
+			port = strconv.Itoa(int(entry.DefaultFullNodePort))
+		}
+		genesis := "-"
+		if entry.HasConstants && entry.GenesisChallenge != "" {
+			genesis = entry.GenesisChallenge
+		}
+		retained := "no"
+		if entry.RetainedSettings {
+			retained = "yes"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Name,
+			selected,
+			prefix,
+			port,
+			genesis,
+			retained,
+			utils.HumanReadableSize(entry.CacheFilesSize),
+			utils.HumanReadableSize(entry.DatabaseSize),
+		)
+	}
+	_ = tw.Flush()
+}
+
+func listNetworks(chiaRoot string, cfg *config.ChiaConfig) []networkListEntry {
+	names := map[string]bool{}
+	if cfg.NetworkOverrides != nil {
+		for name := range cfg.NetworkOverrides.Constants {
+			names[name] = true
+		}
+		for name := range cfg.NetworkOverrides.Config {
+			names[name] = true
+		}
+	}
+
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	selectedNetwork := ""
+	if cfg.SelectedNetwork != nil {
+		selectedNetwork = *cfg.SelectedNetwork
+	}
+
+	entries := make([]networkListEntry, 0, len(sortedNames))
+	for _, name := range sortedNames {
+		entry := networkListEntry{
+			Name:     name,
+			Selected: name == selectedNetwork,
+		}
+		if constants, ok := cfg.NetworkOverrides.Constants[name]; ok {
+			entry.HasConstants = true
+			entry.GenesisChallenge = constants.GenesisChallenge
+		}
+		if netConfig, ok := cfg.NetworkOverrides.Config[name]; ok {
+			entry.HasConfig = true
+			entry.AddressPrefix = netConfig.AddressPrefix
+			entry.DefaultFullNodePort = netConfig.DefaultFullNodePort
+		}
+
+		// Names that are not safe to use in a path have no files to report
+		cacheDir, err := networkDir(chiaRoot, name)
+		if err != nil {
+			slogs.Logr.Warn("skipping files for network with an invalid name", "error", err)
+			entries = append(entries, entry)
+			continue
+		}
+		entry.RetainedSettings = fileExists(path.Join(cacheDir, settingsFileName))
+
+		// The selected network's cache files are in the active location, rather than the network's subdirectory
+		databasePath := path.Join(chiaRoot, "db", fmt.Sprintf("blockchain_v2_%s.sqlite", name))
+		if entry.Selected {
+			cacheDir = path.Join(chiaRoot, "db")
+			if cfg.FullNode.DatabasePath != "" {
+				databasePath = cfg.FullNode.DatabasePath
+				if !path.IsAbs(databasePath) {
+					databasePath = path.Join(chiaRoot, databasePath)
+				}
+			}
+		}
+		entry.CacheFilesSize = fileSize(path.Join(cacheDir, "sub-epoch-summaries")) + fileSize(path.Join(cacheDir, "height-to-hash"))
+		entry.DatabaseSize = fileSize(databasePath)
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fileSize returns the size of the file at path, or 0 if it doesn't exist
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func init() {
+	listCmd.PersistentFlags().Bool("as-json", false, "Output as JSON instead of a table")
+
+	cobra.CheckErr(viper.BindPFlag("net-list-as-json", listCmd.PersistentFlags().Lookup("as-json")))
+
+	networkCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,52 @@
+package network_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/cmd/network"
+)
+
+func TestListNetworks(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootPath, "db", testnetwork), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "db", testnetwork, "settings.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "db", testnetwork, "height-to-hash"), make([]byte, 100), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "db", "height-to-hash"), make([]byte, 50), 0644))
+
+	var out bytes.Buffer
+	network.ListNetworks(&out, true)
+
+	var entries []map[string]any
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+
+	byName := map[string]map[string]any{}
+	for _, entry := range entries {
+		byName[entry["name"].(string)] = entry
+	}
+
+	assert.Contains(t, byName, "mainnet")
+	assert.Contains(t, byName, testnetwork)
+
+	assert.Equal(t, true, byName["mainnet"]["selected"])
+	assert.Equal(t, float64(50), byName["mainnet"]["cache_files_size"])
+	assert.Equal(t, false, byName["mainnet"]["retained_settings"])
+
+	assert.Equal(t, false, byName[testnetwork]["selected"])
+	assert.Equal(t, "txch", byName[testnetwork]["address_prefix"])
+	assert.Equal(t, float64(58445), byName[testnetwork]["default_full_node_port"])
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", byName[testnetwork]["genesis_challenge"])
+	assert.Equal(t, true, byName[testnetwork]["retained_settings"])
+	assert.Equal(t, float64(100), byName[testnetwork]["cache_files_size"])
+}
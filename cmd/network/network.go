@@ -1,6 +1,11 @@
 package network
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
 	"github.com/spf13/cobra"
 
 	"github.com/chia-network/chia-tools/cmd"
@@ -12,6 +17,51 @@ var networkCmd = &cobra.Command{
 	Short: "Utilities for working with chia networks",
 }
 
+// validateNetworkName rejects network names that are not a single, safe path element. Network names are used to build
+// paths in CHIA_ROOT, such as db/<network>, so a name like "" or ".." would point at CHIA_ROOT/db or CHIA_ROOT itself.
+func validateNetworkName(networkName string) error {
+	if networkName == "" || networkName == "." || networkName == ".." || strings.ContainsAny(networkName, `/\`) {
+		return fmt.Errorf("invalid network name %q. Network names can not be empty, . or .., or contain / or \\", networkName)
+	}
+	return nil
+}
+
+// networkDir returns db/<network> in CHIA_ROOT, where a network's cache files, profile, and retained settings are kept
+func networkDir(chiaRoot, networkName string) (string, error) {
+	err := validateNetworkName(networkName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(chiaRoot, "db", networkName), nil
+}
+
+// hasNetwork returns whether the network has constants or config in cfg's network overrides. A config without a
+// network_overrides section doesn't define any networks.
+func hasNetwork(cfg *config.ChiaConfig, networkName string) (hasConstants bool, hasConfig bool) {
+	if cfg.NetworkOverrides == nil {
+		return false, false
+	}
+	_, hasConstants = cfg.NetworkOverrides.Constants[networkName]
+	_, hasConfig = cfg.NetworkOverrides.Config[networkName]
+	return hasConstants, hasConfig
+}
+
+// setNetwork adds the network to cfg's network overrides, replacing any existing definition. The network_overrides
+// section is created if the config doesn't have one.
+func setNetwork(cfg *config.ChiaConfig, networkName string, constants config.NetworkConstants, netConfig config.NetworkConfig) {
+	if cfg.NetworkOverrides == nil {
+		cfg.NetworkOverrides = &config.NetworkOverrides{}
+	}
+	if cfg.NetworkOverrides.Constants == nil {
+		cfg.NetworkOverrides.Constants = map[string]config.NetworkConstants{}
+	}
+	if cfg.NetworkOverrides.Config == nil {
+		cfg.NetworkOverrides.Config = map[string]config.NetworkConfig{}
+	}
+	cfg.NetworkOverrides.Constants[networkName] = constants
+	cfg.NetworkOverrides.Config[networkName] = netConfig
+}
+
 func init() {
 	cmd.RootCmd.AddCommand(networkCmd)
 }
@@ -0,0 +1,49 @@
+package network
+
+import (
+	"path"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNetworkName(t *testing.T) {
+	for _, name := range []string{"mainnet", "testneta", "my-network_1", "net.work"} {
+		assert.NoError(t, validateNetworkName(name), name)
+	}
+	for _, name := range []string{"", ".", "..", "../mainnet", "db/mainnet", `..\mainnet`, "/"} {
+		assert.Error(t, validateNetworkName(name), name)
+	}
+}
+
+func TestNetworkDir(t *testing.T) {
+	dir, err := networkDir("/chia", "testneta")
+	assert.NoError(t, err)
+	assert.Equal(t, path.Join("/chia", "db", "testneta"), dir)
+
+	_, err = networkDir("/chia", "..")
+	assert.Error(t, err)
+}
+
+func TestNetworkFiles_InvalidName(t *testing.T) {
+	// An empty name would otherwise resolve to CHIA_ROOT/db itself
+	files, err := networkFiles(t.TempDir(), "")
+	assert.Error(t, err)
+	assert.Empty(t, files)
+}
+
+func TestSetNetwork_NoNetworkOverrides(t *testing.T) {
+	// A config.yaml without a network_overrides section doesn't define any networks
+	cfg := &config.ChiaConfig{}
+	hasConstants, hasConfig := hasNetwork(cfg, "testneta")
+	assert.False(t, hasConstants)
+	assert.False(t, hasConfig)
+
+	netConfig := config.NetworkConfig{AddressPrefix: "txch", DefaultFullNodePort: 58444}
+	setNetwork(cfg, "testneta", validTestConstants(), netConfig)
+	hasConstants, hasConfig = hasNetwork(cfg, "testneta")
+	assert.True(t, hasConstants)
+	assert.True(t, hasConfig)
+	assert.Equal(t, netConfig, cfg.NetworkOverrides.Config["testneta"])
+}
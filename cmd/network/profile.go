@@ -0,0 +1,139 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const profileFileName = "profile.yaml"
+
+// networkProfile describes the peers and port for a network, replacing the default *.chia.net host names used when
+// switching to the network. Profiles can be set in the chia-tools config file under `network-profiles`, keyed by
+// network name, or stored in db/<network>/profile.yaml, which `network import` writes when the imported definition
+// includes one in its network_profiles section.
+type networkProfile struct {
+	Introducer     string   `yaml:"introducer,omitempty" json:"introducer,omitempty"`
+	DNSIntroducers []string `yaml:"dns_introducers,omitempty" json:"dns_introducers,omitempty"`
+	BootstrapPeers []string `yaml:"bootstrap_peers,omitempty" json:"bootstrap_peers,omitempty"`
+	StaticPeers    []string `yaml:"static_peers,omitempty" json:"static_peers,omitempty"`
+	Port           uint16   `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+func profilePath(chiaRoot, networkName string) (string, error) {
+	dir, err := networkDir(chiaRoot, networkName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, profileFileName), nil
+}
+
+// loadNetworkProfile returns the profile for the network, or nil if there isn't one.
+// Values from the chia-tools config file take precedence over the stored profile in CHIA_ROOT.
+func loadNetworkProfile(chiaRoot, networkName string) (*networkProfile, error) {
+	var profile *networkProfile
+
+	profileFile, err := profilePath(chiaRoot, networkName)
+	if err != nil {
+		return nil, err
+	}
+	storedProfile, err := os.ReadFile(profileFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading network profile: %w", err)
+	}
+	if err == nil {
+		profile = &networkProfile{}
+		err = yaml.Unmarshal(storedProfile, profile)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling network profile: %w", err)
+		}
+	}
+
+	configProfile, err := configNetworkProfile(networkName)
+	if err != nil {
+		return nil, err
+	}
+	if configProfile != nil {
+		if profile == nil {
+			profile = configProfile
+		} else {
+			profile.merge(configProfile)
+		}
+	}
+
+	return profile, nil
+}
+
+// configNetworkProfile returns the profile for the network from the chia-tools config file, or nil if there isn't one.
+// The network-profiles map is looked up by the network name, rather than reading network-profiles.<network> from
+// viper, since network names may contain dots that viper would split the key on. Viper lowercases every key it
+// reads, so a network name that is not found is also looked up in lowercase.
+func configNetworkProfile(networkName string) (*networkProfile, error) {
+	profiles := viper.GetStringMap("network-profiles")
+	value, ok := profiles[networkName]
+	if !ok {
+		value, ok = profiles[strings.ToLower(networkName)]
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	marshalled, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling network profile from chia-tools config: %w", err)
+	}
+	profile := &networkProfile{}
+	err = yaml.Unmarshal(marshalled, profile)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling network profile from chia-tools config: %w", err)
+	}
+	return profile, nil
+}
+
+// merge overrides any values in p with values that are set in other
+func (p *networkProfile) merge(other *networkProfile) {
+	if other.Introducer != "" {
+		p.Introducer = other.Introducer
+	}
+	if len(other.DNSIntroducers) > 0 {
+		p.DNSIntroducers = other.DNSIntroducers
+	}
+	if len(other.BootstrapPeers) > 0 {
+		p.BootstrapPeers = other.BootstrapPeers
+	}
+	if len(other.StaticPeers) > 0 {
+		p.StaticPeers = other.StaticPeers
+	}
+	if other.Port != 0 {
+		p.Port = other.Port
+	}
+}
+
+// saveNetworkProfile stores the profile in CHIA_ROOT so it is used the next time the network is selected
+func saveNetworkProfile(chiaRoot, networkName string, profile networkProfile) error {
+	profileFile, err := profilePath(chiaRoot, networkName)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(profileFile), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating directory for network profile: %w", err)
+	}
+
+	marshalled, err := yaml.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("error marshalling network profile: %w", err)
+	}
+
+	err = os.WriteFile(profileFile, marshalled, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing network profile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigNetworkProfile(t *testing.T) {
+	// Network names may contain dots, and viper lowercases the keys it reads
+	viper.Set("network-profiles", map[string]any{
+		"testnet.2024": map[string]any{"introducer": "introducer.example.com", "port": 4321},
+		"mytestnet":    map[string]any{"dns_introducers": []string{"dns.example.com"}},
+	})
+	defer viper.Set("network-profiles", nil)
+
+	profile, err := configNetworkProfile("testnet.2024")
+	assert.NoError(t, err)
+	assert.Equal(t, &networkProfile{Introducer: "introducer.example.com", Port: 4321}, profile)
+
+	profile, err = configNetworkProfile("MyTestnet")
+	assert.NoError(t, err)
+	assert.Equal(t, &networkProfile{DNSIntroducers: []string{"dns.example.com"}}, profile)
+
+	// Only the exact name matches, not a prefix of it
+	profile, err = configNetworkProfile("testnet")
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
+	"github.com/chia-network/chia-tools/internal/utils"
+)
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Removes a network from the config, and optionally its files from disk",
+	Example: `chia-tools network remove mytestnet
+
+# Also remove the network's cache files, blockchain database and peers files
+chia-tools network remove mytestnet --files
+
+# Show what would be removed without actually removing anything
+chia-tools network remove mytestnet --files --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
+		RemoveNetwork(args[0], viper.GetBool("net-remove-files"), viper.GetBool("net-remove-yes"))
+	}),
+}
+
+// RemoveNetwork deletes a network from the config's network overrides, and optionally removes its files from CHIA_ROOT
+func RemoveNetwork(networkName string, removeFiles bool, skipConfirm bool) {
+	err := validateNetworkName(networkName)
+	if err != nil {
+		slogs.Logr.Fatal("error removing network", "error", err)
+	}
+
+	chiaRoot, err := config.GetChiaRootPath()
+	if err != nil {
+		slogs.Logr.Fatal("error determining chia root", "error", err)
+	}
+	slogs.Logr.Debug("Chia root discovered", "CHIA_ROOT", chiaRoot)
+
+	cfg, err := config.GetChiaConfig()
+	if err != nil {
+		slogs.Logr.Fatal("error loading config", "error", err)
+	}
+	slogs.Logr.Debug("Successfully loaded config")
+
+	if cfg.SelectedNetwork != nil && *cfg.SelectedNetwork == networkName {
+		slogs.Logr.Fatal("refusing to remove the currently selected network. Switch to a different network first", "network", networkName)
+	}
+
+	hasConstants, hasConfig := hasNetwork(cfg, networkName)
+
+	var filesToRemove []string
+	if removeFiles {
+		filesToRemove, err = networkFiles(chiaRoot, networkName)
+		if err != nil {
+			slogs.Logr.Fatal("error finding network files", "error", err)
+		}
+	}
+
+	if !hasConstants && !hasConfig && len(filesToRemove) == 0 {
+		slogs.Logr.Fatal("network does not exist in config's network overrides and has no files to remove", "network", networkName)
+	}
+
+	dryRun := viper.GetBool("dry-run")
+	if dryRun {
+		slogs.Logr.Info("DRY RUN: The following changes would be made")
+	}
+	if hasConstants {
+		slogs.Logr.Info("Will remove network constants from config", "network", networkName)
+	}
+	if hasConfig {
+		slogs.Logr.Info("Will remove network config from config", "network", networkName)
+	}
+	for _, file := range filesToRemove {
+		slogs.Logr.Info("Will remove path", "path", file)
+	}
+
+	if dryRun {
+		slogs.Logr.Info("DRY RUN: No changes were made")
+		return
+	}
+
+	if !utils.ConfirmAction(fmt.Sprintf("Are you sure you would like to remove network %s? (y/N)", networkName), skipConfirm) {
+		slogs.Logr.Error("Cancelled")
+		return
+	}
+
+	if hasConstants || hasConfig {
+		delete(cfg.NetworkOverrides.Constants, networkName)
+		delete(cfg.NetworkOverrides.Config, networkName)
+
+		err = backups.SaveConfig(cfg, path.Join(chiaRoot, "config", "config.yaml"), "network remove")
+		if err != nil {
+			slogs.Logr.Fatal("error saving chia config", "error", err)
+		}
+		slogs.Logr.Info("Removed network from config", "network", networkName)
+	}
+
+	for _, file := range filesToRemove {
+		slogs.Logr.Debug("removing path", "path", file)
+		err = os.RemoveAll(file)
+		if err != nil {
+			slogs.Logr.Fatal("error removing path", "path", file, "error", err)
+		}
+	}
+
+	slogs.Logr.Info("Complete")
+}
+
+// networkFiles returns the paths in CHIA_ROOT that belong to a network that is not currently selected
+func networkFiles(chiaRoot, networkName string) ([]string, error) {
+	cacheDir, err := networkDir(chiaRoot, networkName)
+	if err != nil {
+		return nil, err
+	}
+	database := path.Join(chiaRoot, "db", fmt.Sprintf("blockchain_v2_%s.sqlite", networkName))
+	candidates := []string{
+		cacheDir,
+		database,
+		database + "-wal",
+		database + "-shm",
+		path.Join(chiaRoot, "db", fmt.Sprintf("peers-%s.dat", networkName)),
+		path.Join(chiaRoot, "wallet", "db", fmt.Sprintf("wallet_peers-%s.dat", networkName)),
+	}
+
+	var existing []string
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			existing = append(existing, candidate)
+		}
+	}
+
+	return existing, nil
+}
+
+func init() {
+	removeCmd.PersistentFlags().Bool("files", false, "Also remove the network's cache directory, blockchain database and peers files from CHIA_ROOT")
+	removeCmd.PersistentFlags().BoolP("yes", "y", false, "Skip confirmation")
+
+	cobra.CheckErr(viper.BindPFlag("net-remove-files", removeCmd.PersistentFlags().Lookup("files")))
+	cobra.CheckErr(viper.BindPFlag("net-remove-yes", removeCmd.PersistentFlags().Lookup("yes")))
+
+	networkCmd.AddCommand(removeCmd)
+}
@@ -0,0 +1,90 @@
+package network_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/cmd/network"
+)
+
+func setupNetworkFiles(t *testing.T, rootPath string) []string {
+	files := []string{
+		filepath.Join(rootPath, "db", testnetwork, "height-to-hash"),
+		filepath.Join(rootPath, "db", "blockchain_v2_"+testnetwork+".sqlite"),
+		filepath.Join(rootPath, "db", "peers-"+testnetwork+".dat"),
+		filepath.Join(rootPath, "wallet", "db", "wallet_peers-"+testnetwork+".dat"),
+	}
+	for _, file := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, os.WriteFile(file, []byte("data"), 0644))
+	}
+	return files
+}
+
+func TestRemoveNetwork(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	files := setupNetworkFiles(t, rootPath)
+
+	network.RemoveNetwork(testnetwork, true, true)
+
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.NotContains(t, cfg.NetworkOverrides.Constants, testnetwork)
+	assert.NotContains(t, cfg.NetworkOverrides.Config, testnetwork)
+	assert.Contains(t, cfg.NetworkOverrides.Constants, "mainnet")
+	for _, file := range files {
+		assert.NoFileExists(t, file)
+	}
+	assert.NoDirExists(t, filepath.Join(rootPath, "db", testnetwork))
+}
+
+func TestRemoveNetwork_DryRun(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+	viper.Set("dry-run", true)
+	defer viper.Set("dry-run", false)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	files := setupNetworkFiles(t, rootPath)
+
+	network.RemoveNetwork(testnetwork, true, true)
+
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.Contains(t, cfg.NetworkOverrides.Constants, testnetwork)
+	assert.Contains(t, cfg.NetworkOverrides.Config, testnetwork)
+	for _, file := range files {
+		assert.FileExists(t, file)
+	}
+}
+
+func TestRemoveNetwork_NoNetworkOverrides(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	cfg.NetworkOverrides = nil
+	assert.NoError(t, cfg.SavePath(filepath.Join(rootPath, "config", "config.yaml")))
+	files := setupNetworkFiles(t, rootPath)
+
+	// The network isn't defined in the config, but its files are still removed
+	network.RemoveNetwork(testnetwork, true, true)
+
+	for _, file := range files {
+		assert.NoFileExists(t, file)
+	}
+}
@@ -0,0 +1,176 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// defaultStopTimeout is how long to wait for the daemon to exit after asking it to stop
+const defaultStopTimeout = 60 * time.Second
+
+// servicePollInterval is how often a port is checked while waiting for a service to stop or start
+const servicePollInterval = 500 * time.Millisecond
+
+// errServiceTimeout is returned when the daemon or a service does not stop or start within the timeout
+var errServiceTimeout = errors.New("timed out waiting for chia")
+
+func daemonAddress(cfg *config.ChiaConfig) string {
+	return serviceAddress(cfg, cfg.DaemonPort)
+}
+
+func serviceAddress(cfg *config.ChiaConfig, port uint16) string {
+	return net.JoinHostPort(cfg.SelfHostname, strconv.Itoa(int(port)))
+}
+
+// servicePorts returns the ports a service listens on. They are only closed once the service has shut down, and so
+// no longer has its databases open.
+func servicePorts(cfg *config.ChiaConfig, service rpc.ServiceFullName) []uint16 {
+	switch service {
+	case rpc.ServiceFullNameNode:
+		return []uint16{cfg.FullNode.Port, cfg.FullNode.RPCPort}
+	case rpc.ServiceFullNameWallet:
+		return []uint16{cfg.Wallet.RPCPort}
+	case rpc.ServiceFullNameFarmer:
+		return []uint16{cfg.Farmer.Port, cfg.Farmer.RPCPort}
+	case rpc.ServiceFullNameHarvester:
+		return []uint16{cfg.Harvester.RPCPort}
+	case rpc.ServiceFullNameCrawler:
+		return []uint16{cfg.Seeder.CrawlerConfig.RPCPort}
+	case rpc.ServiceFullNameSeeder:
+		return []uint16{cfg.Seeder.Port}
+	case rpc.ServiceFullNameDataLayer:
+		return []uint16{cfg.DataLayer.RPCPort}
+	case rpc.ServiceFullNameTimelord:
+		return []uint16{cfg.Timelord.RPCPort}
+	}
+	return nil
+}
+
+// chiaCommand returns the path of the chia command, which is used to start the daemon again with --restart
+func chiaCommand() (string, error) {
+	chiaPath, err := exec.LookPath("chia")
+	if err != nil {
+		return "", fmt.Errorf("the chia command was not found on PATH, so chia services can not be restarted: %w", err)
+	}
+	return chiaPath, nil
+}
+
+// stopChiaServices asks the daemon to stop every service and exit, then waits up to timeout for the daemon and each
+// service that was running to stop accepting connections. The daemon can exit before the services it stopped, so
+// their ports are checked as well. Returns the services that were running, which is empty when the daemon was not
+// running.
+func stopChiaServices(cfg *config.ChiaConfig, timeout time.Duration) ([]rpc.ServiceFullName, error) {
+	deadline := time.Now().Add(timeout)
+
+	slogs.Logr.Debug("initializing websocket client to ensure chia is stopped")
+	rpcClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
+	if err != nil {
+		return nil, fmt.Errorf("error initializing RPC client: %w", err)
+	}
+
+	running, _, err := rpcClient.DaemonService.RunningServices()
+	if err != nil {
+		if isConnectionRefused(err) {
+			slogs.Logr.Info("Chia daemon is not running")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing running services: %w", err)
+	}
+
+	var services []rpc.ServiceFullName
+	if running != nil {
+		for _, service := range running.RunningServices {
+			if service != rpc.ServiceFullNameDaemon {
+				services = append(services, service)
+			}
+		}
+	}
+
+	slogs.Logr.Info("Stopping chia services", "services", services)
+	_, _, err = rpcClient.DaemonService.Exit()
+	if err != nil && !isConnectionRefused(err) {
+		return services, fmt.Errorf("error stopping chia services: %w", err)
+	}
+
+	err = waitForPort(daemonAddress(cfg), false, time.Until(deadline), servicePollInterval)
+	if err != nil {
+		return services, err
+	}
+	for _, service := range services {
+		for _, port := range servicePorts(cfg, service) {
+			if port == 0 {
+				continue
+			}
+			err = waitForPort(serviceAddress(cfg, port), false, time.Until(deadline), servicePollInterval)
+			if err != nil {
+				return services, fmt.Errorf("error waiting for %s to stop: %w", service, err)
+			}
+		}
+	}
+	slogs.Logr.Info("Chia services are stopped")
+
+	return services, nil
+}
+
+// startChiaServices starts the daemon with `chia start daemon`, and then starts each service through the daemon. The
+// chia command must be on PATH.
+func startChiaServices(cfg *config.ChiaConfig, services []rpc.ServiceFullName, timeout time.Duration) error {
+	chiaPath, err := chiaCommand()
+	if err != nil {
+		return err
+	}
+
+	slogs.Logr.Info("Starting chia daemon", "command", chiaPath)
+	output, err := exec.Command(chiaPath, "start", "daemon").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running `chia start daemon`: %w: %s", err, output)
+	}
+
+	err = waitForPort(daemonAddress(cfg), true, timeout, servicePollInterval)
+	if err != nil {
+		return err
+	}
+
+	rpcClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
+	if err != nil {
+		return fmt.Errorf("error initializing RPC client: %w", err)
+	}
+
+	for _, service := range services {
+		slogs.Logr.Info("Starting chia service", "service", service)
+		_, _, err = rpcClient.DaemonService.StartService(&rpc.StartServiceOptions{Service: service})
+		if err != nil {
+			return fmt.Errorf("error starting %s: %w", service, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForPort polls address every interval until it accepts connections when open is true, or refuses them when
+// open is false. Returns errServiceTimeout if that does not happen within timeout.
+func waitForPort(address string, open bool, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, interval)
+		if err == nil {
+			_ = conn.Close()
+		}
+		if (err == nil) == open {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w at %s", errServiceTimeout, address)
+		}
+		slogs.Logr.Debug("waiting for chia", "address", address, "open", open)
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,43 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chia-network/chia-tools/cmd"
+)
+
+func TestWaitForPort(t *testing.T) {
+	cmd.InitLogs()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+
+	// The port is open, so waiting for it to open returns straight away, and waiting for it to close times out
+	assert.NoError(t, waitForPort(address, true, time.Second, 10*time.Millisecond))
+	assert.ErrorIs(t, waitForPort(address, false, 100*time.Millisecond, 10*time.Millisecond), errServiceTimeout)
+
+	// Closing the listener part way through the wait is noticed by the next poll
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = listener.Close()
+	}()
+	assert.NoError(t, waitForPort(address, false, 5*time.Second, 10*time.Millisecond))
+	assert.ErrorIs(t, waitForPort(address, true, 100*time.Millisecond, 10*time.Millisecond), errServiceTimeout)
+}
+
+func TestServicePorts(t *testing.T) {
+	cfg, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+
+	// The full node's peer port is checked as well as its RPC port, since it has the blockchain database open
+	assert.Equal(t, []uint16{cfg.FullNode.Port, cfg.FullNode.RPCPort}, servicePorts(cfg, rpc.ServiceFullNameNode))
+	assert.Equal(t, []uint16{cfg.Wallet.RPCPort}, servicePorts(cfg, rpc.ServiceFullNameWallet))
+	assert.Empty(t, servicePorts(cfg, rpc.ServiceFullNameDaemon))
+}
@@ -0,0 +1,213 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const settingsFileName = "settings.json"
+
+// retainedSettingsVersion is the current format of settings.json. Files without a version are from before settings
+// were keyed by config path, and are migrated when they are read.
+const retainedSettingsVersion = 2
+
+// defaultRetainedSettings are the config paths that are always retained when switching networks
+var defaultRetainedSettings = []string{
+	"full_node.dns_servers",
+	"seeder.bootstrap_peers",
+	"seeder.static_peers",
+	"full_node.full_node_peers",
+	"wallet.full_node_peers",
+}
+
+// retainedSettings are the settings we want to keep track of when switching networks so we can swap back to them in
+// the future. Settings are keyed by config path, such as full_node.dns_servers. Additional paths to retain can be set
+// in the chia-tools config file under `retained-settings`.
+type retainedSettings struct {
+	Version  int            `json:"version"`
+	Settings map[string]any `json:"settings"`
+}
+
+// legacyRetainedSettings is the format of settings.json before settings were keyed by config path
+type legacyRetainedSettings struct {
+	DNSServers          []string      `json:"dns_servers"`
+	BootstrapPeers      []string      `json:"bootstrap_peers"`
+	StaticPeers         []string      `json:"static_peers"`
+	FullNodePeers       []config.Peer `json:"full_node_peers"`
+	WalletFullNodePeers []config.Peer `json:"wallet_full_node_peers"`
+}
+
+func settingsPath(chiaRoot, networkName string) (string, error) {
+	dir, err := networkDir(chiaRoot, networkName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, settingsFileName), nil
+}
+
+// retainedSettingPaths returns the default paths to retain, followed by any additional paths from the chia-tools config
+func retainedSettingPaths() []string {
+	paths := append([]string{}, defaultRetainedSettings...)
+	for _, configPath := range viper.GetStringSlice("retained-settings") {
+		if !isDefaultRetainedSetting(configPath) {
+			paths = append(paths, configPath)
+		}
+	}
+	return paths
+}
+
+func isDefaultRetainedSetting(configPath string) bool {
+	for _, defaultPath := range defaultRetainedSettings {
+		if defaultPath == configPath {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotRetainedSettings returns the current value of every retained path in cfg. Paths that are not in the config
+// are skipped.
+func snapshotRetainedSettings(cfg *config.ChiaConfig) (*retainedSettings, error) {
+	settings := &retainedSettings{
+		Version:  retainedSettingsVersion,
+		Settings: map[string]any{},
+	}
+	for _, configPath := range retainedSettingPaths() {
+		value, err := cfg.GetFieldByPath(configPathSlice(configPath))
+		if err != nil {
+			slogs.Logr.Warn("retained setting not found in config, skipping", "path", configPath)
+			continue
+		}
+		settings.Settings[configPath], err = toGenericSetting(value)
+		if err != nil {
+			return nil, fmt.Errorf("error retaining setting %s: %w", configPath, err)
+		}
+	}
+	return settings, nil
+}
+
+// loadRetainedSettings returns the settings retained for the network, or nil if there aren't any
+func loadRetainedSettings(chiaRoot, networkName string) (*retainedSettings, error) {
+	settingsFile, err := settingsPath(chiaRoot, networkName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading retained settings: %w", err)
+	}
+	return parseRetainedSettings(data)
+}
+
+// parseRetainedSettings parses settings.json, migrating files from before settings were keyed by config path
+func parseRetainedSettings(data []byte) (*retainedSettings, error) {
+	settings := &retainedSettings{}
+	err := json.Unmarshal(data, settings)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling retained settings: %w", err)
+	}
+	if settings.Version > retainedSettingsVersion {
+		return nil, fmt.Errorf("retained settings are version %d, but only up to version %d is supported", settings.Version, retainedSettingsVersion)
+	}
+	if settings.Version != 0 {
+		if settings.Settings == nil {
+			settings.Settings = map[string]any{}
+		}
+		return settings, nil
+	}
+
+	legacy := &legacyRetainedSettings{}
+	err = json.Unmarshal(data, legacy)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling retained settings: %w", err)
+	}
+	slogs.Logr.Debug("migrating retained settings from the legacy format")
+
+	settings = &retainedSettings{
+		Version:  retainedSettingsVersion,
+		Settings: map[string]any{},
+	}
+	for configPath, value := range map[string]any{
+		"full_node.dns_servers":     legacy.DNSServers,
+		"seeder.bootstrap_peers":    legacy.BootstrapPeers,
+		"seeder.static_peers":       legacy.StaticPeers,
+		"full_node.full_node_peers": legacy.FullNodePeers,
+		"wallet.full_node_peers":    legacy.WalletFullNodePeers,
+	} {
+		settings.Settings[configPath], err = toGenericSetting(value)
+		if err != nil {
+			return nil, fmt.Errorf("error migrating retained setting %s: %w", configPath, err)
+		}
+	}
+	return settings, nil
+}
+
+// decode decodes the retained value of configPath into out. Returns false if the path was not retained.
+func (s *retainedSettings) decode(configPath string, out any) (bool, error) {
+	value, ok := s.Settings[configPath]
+	if !ok {
+		return false, nil
+	}
+	marshalled, err := yaml.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("error marshalling retained setting %s: %w", configPath, err)
+	}
+	err = yaml.Unmarshal(marshalled, out)
+	if err != nil {
+		return false, fmt.Errorf("error unmarshalling retained setting %s: %w", configPath, err)
+	}
+	return true, nil
+}
+
+// typedValue decodes the retained value of configPath into the same type as the current value in cfg, so it can be
+// set with SetFieldByPath. Returns false if the path was not retained.
+func (s *retainedSettings) typedValue(cfg *config.ChiaConfig, configPath string) (any, bool, error) {
+	current, err := cfg.GetFieldByPath(configPathSlice(configPath))
+	if err != nil {
+		return nil, false, fmt.Errorf("error finding retained setting %s in the config: %w", configPath, err)
+	}
+	if current == nil {
+		return nil, false, fmt.Errorf("retained setting %s has no type in the config", configPath)
+	}
+	typed := reflect.New(reflect.TypeOf(current))
+	ok, err := s.decode(configPath, typed.Interface())
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return typed.Elem().Interface(), true, nil
+}
+
+// toGenericSetting converts a config value to the plain maps, lists, and scalars it is stored as, using the same
+// field names as config.yaml
+func toGenericSetting(value any) (any, error) {
+	marshalled, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	err = yaml.Unmarshal(marshalled, &generic)
+	if err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// configPathSlice splits a dotted config path, such as full_node.dns_servers, into its parts
+func configPathSlice(configPath string) []string {
+	for _, pathSlice := range config.ParsePathsFromStrings([]string{configPath}, false) {
+		return pathSlice
+	}
+	return nil
+}
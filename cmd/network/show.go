@@ -1,85 +1,183 @@
 package network
 
 import (
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"text/tabwriter"
+	"time"
 
-	"github.com/chia-network/go-chia-libs/pkg/config"
-	"github.com/chia-network/go-chia-libs/pkg/rpc"
 	"github.com/chia-network/go-modules/pkg/slogs"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/chia-tools/internal/probe"
 )
 
 // showCmd represents the show command
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show information about the currently selected/running network",
+	Example: `chia-tools network show
+
+# Exit with status 2 if any running service is on a different network than the config
+chia-tools network show --check
+
+# Show the networks of a remote node's services, using a copy of its config/ssl directory
+chia-tools network show --host 10.0.0.5 --cert-dir ./node5/config/ssl --harvester-host 10.0.0.6
+
+# Show the networks of every node in an inventory file as a single table
+chia-tools network show --inventory nodes.yaml`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ShowNetworkInfo()
+		timeout := viper.GetDuration("net-show-timeout")
+		asJSON := viper.GetBool("net-show-as-json")
+
+		var mismatched []string
+		if viper.GetString("net-show-inventory") != "" || asJSON {
+			if asJSON {
+				// Keep stdout clean so the output can be piped into other tools
+				slogs.Init(viper.GetString("log-level"), slogs.WithWriter(os.Stderr))
+			}
+			fleet, err := probe.FleetFromFlags("net-show", timeout)
+			if err != nil {
+				slogs.Logr.Fatal("error probing nodes", "error", err)
+			}
+			mismatched = ShowFleetNetworkInfo(os.Stdout, fleet, asJSON)
+		} else {
+			mismatched = ShowNetworkInfo(probe.TargetFromFlags("net-show"), timeout)
+		}
+		if viper.GetBool("net-show-check") && len(mismatched) > 0 {
+			slogs.Logr.Error("running services are not on the network selected in the config", "services", mismatched)
+			os.Exit(exitCodeNetworkMismatch)
+		}
 	},
 }
 
-// ShowNetworkInfo outputs network information from the configuration and any running services
-func ShowNetworkInfo() {
-	chiaRoot, err := config.GetChiaRootPath()
-	if err != nil {
-		slogs.Logr.Fatal("error determining chia root", "error", err)
-	}
-	slogs.Logr.Debug("Chia root discovered", "CHIA_ROOT", chiaRoot)
+// exitCodeNetworkMismatch is the exit code for `network show --check` when a running service is on a different
+// network than the config. It is distinct from the exit code for other errors, so monitoring can alert on it.
+const exitCodeNetworkMismatch = 2
 
-	cfg, err := config.GetChiaConfig()
+// ShowNetworkInfo outputs network information from the configuration and any running services on the target, waiting
+// up to timeout for each service. Returns the running services that are on a different network than the config.
+func ShowNetworkInfo(target probe.Target, timeout time.Duration) []string {
+	cfg, err := target.Config()
 	if err != nil {
 		slogs.Logr.Fatal("error loading config", "error", err)
 	}
 	slogs.Logr.Debug("Successfully loaded config")
 
-	configNetwork := *cfg.SelectedNetwork
-
-	slogs.Logr.Debug("initializing websocket client")
-	websocketClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
+	services, err := probe.Services(target, timeout)
 	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
+		slogs.Logr.Fatal("error initializing RPC clients", "error", err)
 	}
-	slogs.Logr.Debug("initializing http client")
-	rpcClient, err := rpc.NewClient(rpc.ConnectionModeHTTP, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
-	if err != nil {
-		slogs.Logr.Fatal("error initializing websocket RPC client", "error", err)
+
+	node := probe.NodeResults{Node: target.Host, Results: probe.Networks(services, timeout)}
+	// The local config's network is not the network of a remote node, so it is left unknown
+	if cfg.SelectedNetwork != nil && target.HasNodeConfig() {
+		node.Network = *cfg.SelectedNetwork
+	}
+	return ShowNodeNetworkInfo(os.Stdout, node)
+}
+
+// ShowNodeNetworkInfo outputs the network selected in a node's config, and the network of each of its services that
+// was probed. Returns the running services that are on a different network than the config.
+func ShowNodeNetworkInfo(w io.Writer, node probe.NodeResults) []string {
+	warnUnknownConfigNetwork(node)
+
+	configNetwork := node.Network
+	if configNetwork == "" {
+		configNetwork = "Unknown"
 	}
+	tw := tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Config\t", configNetwork)
 
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-	_, _ = fmt.Fprintln(w, "Config\t", configNetwork)
-
-	networkHelper(w, websocketClient.DaemonService, "Daemon")
-	networkHelper(w, rpcClient.FullNodeService, "Full Node")
-	networkHelper(w, rpcClient.WalletService, "Wallet")
-	networkHelper(w, rpcClient.FarmerService, "Farmer")
-	networkHelper(w, rpcClient.HarvesterService, "Harvester")
-	networkHelper(w, rpcClient.CrawlerService, "Crawler")
-	networkHelper(w, rpcClient.DataLayerService, "Data Layer")
-	networkHelper(w, rpcClient.TimelordService, "Timelord")
-	_ = w.Flush()
+	var mismatched []string
+	for _, result := range node.Results {
+		if networkHelper(tw, result, node.Network) {
+			mismatched = append(mismatched, result.Label)
+		}
+	}
+	_ = tw.Flush()
+
+	return mismatched
 }
 
-type hasNetworkName interface {
-	GetNetworkInfo(opts *rpc.GetNetworkInfoOptions) (*rpc.GetNetworkInfoResponse, *http.Response, error)
+// warnUnknownConfigNetwork logs that a node's services can't be checked for network mismatches, since the network
+// selected in its config is not known
+func warnUnknownConfigNetwork(node probe.NodeResults) {
+	if node.Err == nil && node.Network == "" {
+		slogs.Logr.Warn("the node's config is not known, so its services are not checked against the config network. Copy the node's config.yaml next to its --cert-dir to check them", "node", node.Node)
+	}
 }
 
-func networkHelper(w io.Writer, service hasNetworkName, label string) {
-	network, _, err := service.GetNetworkInfo(&rpc.GetNetworkInfoOptions{})
-	if err != nil {
-		slogs.Logr.Debug("error getting network info from daemon", "error", err)
-		network = &rpc.GetNetworkInfoResponse{}
+// networkHelper outputs the network of a service. Returns true if the service is running on a different network than
+// configNetwork. Nothing is a mismatch when configNetwork is not known.
+func networkHelper(w io.Writer, result probe.Result, configNetwork string) bool {
+	switch {
+	case errors.Is(result.Err, probe.ErrTimeout):
+		_, _ = fmt.Fprintln(w, result.Label, "\t", "Not Responding")
+		return false
+	case !result.Running:
+		_, _ = fmt.Fprintln(w, result.Label, "\t", "Not Running")
+		return false
+	case result.Network == "":
+		_, _ = fmt.Fprintln(w, result.Label, "\t", "Unknown")
+		return false
+	case configNetwork != "" && result.Network != configNetwork:
+		_, _ = fmt.Fprintln(w, result.Label, "\t", result.Network, "\t", "MISMATCH")
+		return true
 	}
-	if network == nil {
-		slogs.Logr.Debug("no network info found", "service", label)
-		network = &rpc.GetNetworkInfoResponse{}
+	_, _ = fmt.Fprintln(w, result.Label, "\t", result.Network)
+	return false
+}
+
+// ShowFleetNetworkInfo outputs the network of every service on every node, as a table or JSON. Returns the running
+// services that are on a different network than their node's config, as node/service.
+func ShowFleetNetworkInfo(w io.Writer, fleet []probe.NodeResults, asJSON bool) []string {
+	var mismatched []string
+	for _, node := range fleet {
+		warnUnknownConfigNetwork(node)
+		for _, result := range node.Results {
+			if result.Running && result.Network != "" && node.Network != "" && result.Network != node.Network {
+				mismatched = append(mismatched, node.Node+"/"+result.Label)
+			}
+		}
 	}
-	_, _ = fmt.Fprintln(w, label, "\t", network.NetworkName.OrElse("Not Running"))
+
+	if asJSON {
+		err := probe.WriteFleetJSON(w, fleet)
+		if err != nil {
+			slogs.Logr.Fatal("error writing results", "error", err)
+		}
+		return mismatched
+	}
+
+	probe.WriteFleetTable(w, fleet, fleetNetworkCell)
+	return mismatched
+}
+
+// fleetNetworkCell formats the network of a service for the fleet table
+func fleetNetworkCell(node probe.NodeResults, result probe.Result) string {
+	switch {
+	case !result.Running:
+		return probe.Status(result)
+	case result.Network == "":
+		return "Unknown"
+	case node.Network != "" && result.Network != node.Network:
+		return result.Network + " (MISMATCH)"
+	}
+	return result.Network
 }
 
 func init() {
+	showCmd.PersistentFlags().Bool("check", false, "Exit with a non-zero status if any running service is on a different network than the config")
+	showCmd.PersistentFlags().Duration("timeout", probe.DefaultTimeout, "How long to wait for each service to respond")
+	showCmd.PersistentFlags().Bool("as-json", false, "Output as JSON, with a row for each node and service")
+	probe.AddFlags(showCmd.PersistentFlags(), "net-show")
+	cobra.CheckErr(viper.BindPFlag("net-show-check", showCmd.PersistentFlags().Lookup("check")))
+	cobra.CheckErr(viper.BindPFlag("net-show-timeout", showCmd.PersistentFlags().Lookup("timeout")))
+	cobra.CheckErr(viper.BindPFlag("net-show-as-json", showCmd.PersistentFlags().Lookup("as-json")))
+
 	networkCmd.AddCommand(showCmd)
 }
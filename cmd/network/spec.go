@@ -0,0 +1,107 @@
+package network
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// generateSpec is a declarative network definition for `network generate --spec`. The constants and config use the
+// same keys as network_overrides in the chia config, and any field may be set. Pre-farm puzzle hashes may be
+// addresses, as with the flags. For example:
+//
+//	network: examplenet
+//	genesis_seed: examplenet-2024-01
+//	constants:
+//	  MIN_PLOT_SIZE: 20
+//	  EPOCH_BLOCKS: 384
+//	config:
+//	  address_prefix: txch
+//	  default_full_node_port: 58445
+type generateSpec struct {
+	Network     string                   `yaml:"network" json:"network"`
+	GenesisSeed string                   `yaml:"genesis_seed" json:"genesis_seed"`
+	Constants   *config.NetworkConstants `yaml:"constants" json:"constants"`
+	Config      *config.NetworkConfig    `yaml:"config" json:"config"`
+}
+
+// loadGenerateSpec reads a YAML or JSON spec from file, or stdin when file is "-". Values in the spec are decoded on
+// top of constants and cfg, so anything the spec leaves out keeps its existing value. Unknown keys are an error, so
+// typos in the spec are not silently ignored.
+func loadGenerateSpec(file string, constants *config.NetworkConstants, cfg *config.NetworkConfig) (*generateSpec, error) {
+	data, err := readSource("", file, false)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &generateSpec{
+		Constants: constants,
+		Config:    cfg,
+	}
+	err = decodeStrict(data, spec)
+	if err != nil {
+		// The output of network generate --with-constants can also be used as a spec
+		generated, generatedErr := loadGeneratedSpec(data, constants, cfg)
+		if generatedErr != nil {
+			return nil, fmt.Errorf("error unmarshalling network spec: %w", err)
+		}
+		return generated, nil
+	}
+
+	return spec, nil
+}
+
+// loadGeneratedSpec converts the output of `network generate --with-constants` into a spec. The output must define
+// exactly one network.
+func loadGeneratedSpec(data []byte, constants *config.NetworkConstants, cfg *config.NetworkConfig) (*generateSpec, error) {
+	generated := &struct {
+		GenesisSeed string               `yaml:"genesis_seed"`
+		Constants   map[string]yaml.Node `yaml:"constants"`
+		Config      map[string]yaml.Node `yaml:"config"`
+	}{}
+	err := decodeStrict(data, generated)
+	if err != nil {
+		return nil, err
+	}
+	if len(generated.Constants) != 1 {
+		return nil, fmt.Errorf("generated network definition must define exactly one network, found %d", len(generated.Constants))
+	}
+
+	for name, constantsNode := range generated.Constants {
+		configNode, ok := generated.Config[name]
+		if !ok {
+			return nil, fmt.Errorf("generated network definition has no config for %s", name)
+		}
+		err = constantsNode.Decode(constants)
+		if err != nil {
+			return nil, err
+		}
+		err = configNode.Decode(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &generateSpec{
+			Network:     name,
+			GenesisSeed: generated.GenesisSeed,
+			Constants:   constants,
+			Config:      cfg,
+		}, nil
+	}
+	return nil, nil
+}
+
+// decodeStrict decodes YAML or JSON into out, returning an error for unknown keys
+func decodeStrict(data []byte, out any) error {
+	// JSON is valid YAML, so both are decoded the same way
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(out)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package network
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func writeSpec(t *testing.T, name, contents string) string {
+	specFile := path.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(specFile, []byte(contents), 0644))
+	return specFile
+}
+
+func TestLoadGenerateSpec(t *testing.T) {
+	specs := map[string]string{
+		"spec.yaml": `network: specnet
+genesis_seed: specnet-seed
+constants:
+  MIN_PLOT_SIZE: 20
+  HARD_FORK2_HEIGHT: 1000
+config:
+  address_prefix: tspec
+`,
+		"spec.json": `{"network": "specnet", "genesis_seed": "specnet-seed", "constants": {"MIN_PLOT_SIZE": 20, "HARD_FORK2_HEIGHT": 1000}, "config": {"address_prefix": "tspec"}}`,
+	}
+
+	for name, contents := range specs {
+		t.Run(name, func(t *testing.T) {
+			constants := &config.NetworkConstants{
+				MinPlotSize: 18,
+				EpochBlocks: 768,
+			}
+			cfg := &config.NetworkConfig{
+				AddressPrefix:       "txch",
+				DefaultFullNodePort: 58445,
+			}
+
+			spec, err := loadGenerateSpec(writeSpec(t, name, contents), constants, cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, "specnet", spec.Network)
+			assert.Equal(t, "specnet-seed", spec.GenesisSeed)
+
+			// Values in the spec replace the existing values, and everything else is kept
+			assert.Equal(t, uint8(20), constants.MinPlotSize)
+			assert.Equal(t, uint32(1000), *constants.HardFork2Height)
+			assert.Equal(t, uint32(768), constants.EpochBlocks)
+			assert.Equal(t, "tspec", cfg.AddressPrefix)
+			assert.Equal(t, uint16(58445), cfg.DefaultFullNodePort)
+		})
+	}
+}
+
+func TestLoadGenerateSpec_UnknownField(t *testing.T) {
+	specFile := writeSpec(t, "spec.yaml", "constants:\n  MIN_PLOT_SIZ: 20\n")
+	_, err := loadGenerateSpec(specFile, &config.NetworkConstants{}, &config.NetworkConfig{})
+	assert.Error(t, err)
+}
+
+func TestApplyGenerateFlags_OverridesSpec(t *testing.T) {
+	constants := &config.NetworkConstants{}
+	cfg := &config.NetworkConfig{}
+	_, err := loadGenerateSpec(writeSpec(t, "spec.yaml", "constants:\n  MIN_PLOT_SIZE: 20\n  EPOCH_BLOCKS: 384\n"), constants, cfg)
+	assert.NoError(t, err)
+
+	viper.Set("tn-gen-min-plot-size", 22)
+	defer viper.Set("tn-gen-min-plot-size", nil)
+	applyGenerateFlags(constants, cfg, true)
+
+	assert.Equal(t, uint8(22), constants.MinPlotSize)
+	assert.Equal(t, uint32(384), constants.EpochBlocks)
+}
+
+func TestLoadGenerateSpec_GeneratedNetwork(t *testing.T) {
+	// The output of --with-constants can be used as a spec to generate the same network again
+	overrides := testNetworkOverrides()
+	examplenet := overrides.Constants["examplenet"]
+	examplenet.GenesisChallenge = seedChallenge("team-a")
+	examplenet.AggSigMeAdditionalData = examplenet.GenesisChallenge
+	overrides.Constants["examplenet"] = examplenet
+	generated, err := yaml.Marshal(generatedNetwork{GenesisSeed: "team-a", NetworkOverrides: *overrides})
+	assert.NoError(t, err)
+
+	constants := &config.NetworkConstants{EpochBlocks: 768}
+	cfg := &config.NetworkConfig{}
+	spec, err := loadGenerateSpec(writeSpec(t, "generated.yaml", string(generated)), constants, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "examplenet", spec.Network)
+	assert.Equal(t, "team-a", spec.GenesisSeed)
+	assert.Equal(t, uint8(18), constants.MinPlotSize)
+	assert.Equal(t, uint32(768), constants.EpochBlocks)
+	assert.Equal(t, "txch", cfg.AddressPrefix)
+
+	challenge, seed, err := genesisChallenge(spec.Network, spec.GenesisSeed, constants.GenesisChallenge, false)
+	assert.NoError(t, err)
+	assert.Equal(t, examplenet.GenesisChallenge, challenge)
+	assert.Equal(t, "team-a", seed)
+}
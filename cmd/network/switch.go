@@ -7,6 +7,8 @@ import (
 	"net"
 	"os"
 	"path"
+	"reflect"
+	"sort"
 	"syscall"
 
 	"github.com/chia-network/go-chia-libs/pkg/config"
@@ -15,18 +17,52 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/chia-network/chia-tools/cmd"
+	"github.com/chia-network/chia-tools/internal/backups"
 	"github.com/chia-network/chia-tools/internal/connect"
 )
 
 var switchCmd = &cobra.Command{
-	Use:     "switch",
-	Short:   "Switches the active network on this machine",
-	Example: "chia-tools network switch testnet11",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:   "switch",
+	Short: "Switches the active network on this machine",
+	Example: `chia-tools network switch testnet11
+
+# Complete a network switch that was interrupted
+chia-tools network switch --resume
+
+# Revert a network switch that was interrupted
+chia-tools network switch --rollback
+
+# Show the config changes and file operations a switch would make, without making them
+chia-tools network switch testnet11 --dry-run
+
+# Start the services that were running again once the switch completes
+chia-tools network switch testnet11 --restart`,
+	Args: cobra.RangeArgs(0, 1),
+	Run: cmd.WithChiaRootLock(func(cmd *cobra.Command, args []string) {
+		resume := viper.GetBool("switch-resume")
+		rollback := viper.GetBool("switch-rollback")
+		if resume && rollback {
+			slogs.Logr.Fatal("--resume and --rollback can not be used together")
+		}
+		if resume || rollback {
+			if len(args) > 0 {
+				slogs.Logr.Fatal("a network name can not be provided with --resume or --rollback")
+			}
+			if resume {
+				ResumeNetworkSwitch(true)
+			} else {
+				RollbackNetworkSwitch(true)
+			}
+			return
+		}
+
+		if len(args) != 1 {
+			slogs.Logr.Fatal("a network name to switch to is required")
+		}
 		networkName := args[0]
 		SwitchNetwork(networkName, true)
-	},
+	}),
 }
 
 func init() {
@@ -34,34 +70,48 @@ func init() {
 	switchCmd.PersistentFlags().String("dns-introducer", "", "Override the default values for dns-introducer host")
 	switchCmd.PersistentFlags().String("bootstrap-peer", "", "Override the default value for seeder bootstrap peer")
 	switchCmd.PersistentFlags().Uint16("full-node-port", 0, "Override the default values for the full node port")
+	switchCmd.PersistentFlags().Bool("resume", false, "Complete a network switch that was interrupted")
+	switchCmd.PersistentFlags().Bool("rollback", false, "Revert a network switch that was interrupted")
+	switchCmd.PersistentFlags().Bool("as-json", false, "Output the --dry-run plan as JSON instead of text")
+	switchCmd.PersistentFlags().Bool("restart", false, "Start the services that were running again once the switch completes, or once --resume or --rollback finishes. The chia command must be on PATH")
+	switchCmd.PersistentFlags().Duration("stop-timeout", defaultStopTimeout, "How long to wait for chia services to stop, or the daemon to start with --restart")
 
 	cobra.CheckErr(viper.BindPFlag("switch-introducer", switchCmd.PersistentFlags().Lookup("introducer")))
 	cobra.CheckErr(viper.BindPFlag("switch-dns-introducer", switchCmd.PersistentFlags().Lookup("dns-introducer")))
 	cobra.CheckErr(viper.BindPFlag("switch-bootstrap-peer", switchCmd.PersistentFlags().Lookup("bootstrap-peer")))
 	cobra.CheckErr(viper.BindPFlag("switch-full-node-port", switchCmd.PersistentFlags().Lookup("full-node-port")))
+	cobra.CheckErr(viper.BindPFlag("switch-resume", switchCmd.PersistentFlags().Lookup("resume")))
+	cobra.CheckErr(viper.BindPFlag("switch-rollback", switchCmd.PersistentFlags().Lookup("rollback")))
+	cobra.CheckErr(viper.BindPFlag("switch-as-json", switchCmd.PersistentFlags().Lookup("as-json")))
+	cobra.CheckErr(viper.BindPFlag("switch-restart", switchCmd.PersistentFlags().Lookup("restart")))
+	cobra.CheckErr(viper.BindPFlag("switch-stop-timeout", switchCmd.PersistentFlags().Lookup("stop-timeout")))
 
 	networkCmd.AddCommand(switchCmd)
 }
 
-// retainedSettings are the settings we want to keep track of when switching networks so we can swap back to them in the future
-type retainedSettings struct {
-	DNSServers          []string      `json:"dns_servers"`
-	BootstrapPeers      []string      `json:"bootstrap_peers"`
-	StaticPeers         []string      `json:"static_peers"`
-	FullNodePeers       []config.Peer `json:"full_node_peers"`
-	WalletFullNodePeers []config.Peer `json:"wallet_full_node_peers"`
-}
-
 // SwitchNetwork implements the logic to swap networks
 func SwitchNetwork(networkName string, checkForRunningNode bool) {
 	slogs.Logr.Info("Swapping to network", "network", networkName)
 
+	err := validateNetworkName(networkName)
+	if err != nil {
+		slogs.Logr.Fatal("error switching networks", "error", err)
+	}
+
 	chiaRoot, err := config.GetChiaRootPath()
 	if err != nil {
 		slogs.Logr.Fatal("error determining chia root", "error", err)
 	}
 	slogs.Logr.Debug("Chia root discovered", "CHIA_ROOT", chiaRoot)
 
+	existingJournal, err := loadSwitchJournal(chiaRoot)
+	if err != nil {
+		slogs.Logr.Fatal("error checking for an incomplete network switch", "error", err)
+	}
+	if existingJournal != nil {
+		slogs.Logr.Fatal("an incomplete network switch was found. Run `chia-tools network switch` with --resume or --rollback first", "from", existingJournal.From, "to", existingJournal.To)
+	}
+
 	cfg, err := config.GetChiaConfig()
 	if err != nil {
 		slogs.Logr.Fatal("error loading config", "error", err)
@@ -76,102 +126,37 @@ func SwitchNetwork(networkName string, checkForRunningNode bool) {
 	}
 
 	// Ensure we have network constants for the network trying to be swapped to
-	if _, ok := cfg.NetworkOverrides.Constants[networkName]; !ok {
+	hasConstants, hasConfig := hasNetwork(cfg, networkName)
+	if !hasConstants {
 		slogs.Logr.Fatal("selected network does not exist in config's network override constants", "network", networkName)
 	}
-	var (
-		netConfig config.NetworkConfig
-		ok        bool
-	)
-	if netConfig, ok = cfg.NetworkOverrides.Config[networkName]; !ok {
+	if !hasConfig {
 		slogs.Logr.Fatal("selected network does not exist in config's network override config", "network", networkName)
 	}
+	netConfig := cfg.NetworkOverrides.Config[networkName]
 
-	// Ensure a folder to store the current network's sub-epoch-summaries and height-to-hash files exists
-	cacheFileDirOldNetwork := path.Join(chiaRoot, "db", currentNetwork)
-	cacheFileDirNewNetwork := path.Join(chiaRoot, "db", networkName)
-
-	slogs.Logr.Debug("ensuring directory exists for current network cache files", "directory", cacheFileDirOldNetwork)
-	err = os.MkdirAll(cacheFileDirOldNetwork, 0755)
-	if err != nil {
-		slogs.Logr.Fatal("error creating cache file directory for current network", "error", err, "directory", cacheFileDirOldNetwork)
-	}
-
-	slogs.Logr.Debug("ensuring directory exists for new network cache files", "directory", cacheFileDirNewNetwork)
-	err = os.MkdirAll(cacheFileDirNewNetwork, 0755)
-	if err != nil {
-		slogs.Logr.Fatal("error creating cache file directory for new network", "error", err, "directory", cacheFileDirNewNetwork)
-	}
-
-	previousSettings := retainedSettings{
-		DNSServers:          cfg.FullNode.DNSServers,
-		BootstrapPeers:      cfg.Seeder.BootstrapPeers,
-		StaticPeers:         cfg.Seeder.StaticPeers,
-		FullNodePeers:       cfg.FullNode.FullNodePeers,
-		WalletFullNodePeers: cfg.Wallet.FullNodePeers,
-	}
-	marshalled, err := json.Marshal(previousSettings)
+	// Folders to store each network's sub-epoch-summaries and height-to-hash files
+	cacheFileDirOldNetwork, err := networkDir(chiaRoot, currentNetwork)
 	if err != nil {
-		slogs.Logr.Fatal("error marshalling retained settings to json", "error", err)
+		slogs.Logr.Fatal("current network can not be switched away from", "error", err)
 	}
-	err = os.WriteFile(path.Join(cacheFileDirOldNetwork, "settings.json"), marshalled, 0644)
+	cacheFileDirNewNetwork, err := networkDir(chiaRoot, networkName)
 	if err != nil {
-		slogs.Logr.Fatal("error writing settings for old network", "error", err)
-	}
-
-	var settingsToRestore *retainedSettings
-	newSettingsPath := path.Join(cacheFileDirNewNetwork, "settings.json")
-	if _, err := os.Stat(newSettingsPath); err == nil {
-		settings, err := os.ReadFile(newSettingsPath)
-		if err != nil {
-			slogs.Logr.Fatal("error reading stored settings for the new network", "error", err)
-		}
-		settingsToRestore = &retainedSettings{}
-		err = json.Unmarshal(settings, settingsToRestore)
-		if err != nil {
-			slogs.Logr.Fatal("error unmarshalling stored settings for the new network", "error", err)
-		}
+		slogs.Logr.Fatal("error switching networks", "error", err)
 	}
 
-	// Check if Full Node is running
-	if checkForRunningNode {
-		slogs.Logr.Debug("initializing websocket client to ensure chia is stopped")
-		rpcClient, err := rpc.NewClient(rpc.ConnectionModeWebsocket, rpc.WithAutoConfig(), rpc.WithSyncWebsocket())
-		if err != nil {
-			slogs.Logr.Fatal("error initializing RPC client", "error", err)
-		}
-
-		slogs.Logr.Info("Ensuring chia services are stopped")
-		_, _, err = rpcClient.DaemonService.Exit()
-		if err != nil {
-			if !isConnectionRefused(err) {
-				slogs.Logr.Fatal("error stopping chia services", "error", err)
-			}
-		}
-	}
-
-	// Safe to move files now
-	activeSubEpochSummariesPath := path.Join(chiaRoot, "db", "sub-epoch-summaries")
-	activeHeightToHashPath := path.Join(chiaRoot, "db", "height-to-hash")
-
-	// Move current cache files to the network subdir
-	err = moveAndOverwriteFile(activeSubEpochSummariesPath, path.Join(cacheFileDirOldNetwork, "sub-epoch-summaries"))
+	previousSettings, err := snapshotRetainedSettings(cfg)
 	if err != nil {
-		slogs.Logr.Fatal("error moving sub-epoch-summaries file", "error", err)
+		slogs.Logr.Fatal("error retaining settings for the current network", "error", err)
 	}
-	err = moveAndOverwriteFile(activeHeightToHashPath, path.Join(cacheFileDirOldNetwork, "height-to-hash"))
+	marshalledSettings, err := json.Marshal(previousSettings)
 	if err != nil {
-		slogs.Logr.Fatal("error moving height-to-hash file", "error", err)
+		slogs.Logr.Fatal("error marshalling retained settings to json", "error", err)
 	}
 
-	// Move old cached files to active dir
-	err = moveAndOverwriteFile(path.Join(cacheFileDirNewNetwork, "sub-epoch-summaries"), activeSubEpochSummariesPath)
-	if err != nil {
-		slogs.Logr.Fatal("error moving sub-epoch-summaries file", "error", err)
-	}
-	err = moveAndOverwriteFile(path.Join(cacheFileDirNewNetwork, "height-to-hash"), activeHeightToHashPath)
+	settingsToRestore, err := loadRetainedSettings(chiaRoot, networkName)
 	if err != nil {
-		slogs.Logr.Fatal("error moving height-to-hash file", "error", err)
+		slogs.Logr.Fatal("error loading stored settings for the new network", "error", err)
 	}
 
 	introducerHost := "introducer.chia.net"
@@ -192,23 +177,41 @@ func SwitchNetwork(networkName string, checkForRunningNode bool) {
 		bootstrapPeers = []string{fmt.Sprintf("node-%s.chia.net", networkName)}
 	}
 
-	// Any stored settings for the new network should be applied here, before any flags override them
-	if settingsToRestore != nil {
-		slogs.Logr.Info("restoring stored settings for this network")
-		if len(settingsToRestore.DNSServers) > 0 {
-			dnsIntroducerHosts = settingsToRestore.DNSServers
+	// A profile for the network replaces the default hosts above
+	profile, err := loadNetworkProfile(chiaRoot, networkName)
+	if err != nil {
+		slogs.Logr.Fatal("error loading network profile", "error", err)
+	}
+	if profile != nil {
+		slogs.Logr.Info("applying network profile", "network", networkName)
+		if profile.Introducer != "" {
+			introducerHost = profile.Introducer
 		}
-		if len(settingsToRestore.BootstrapPeers) > 0 {
-			bootstrapPeers = settingsToRestore.BootstrapPeers
+		if len(profile.DNSIntroducers) > 0 {
+			dnsIntroducerHosts = profile.DNSIntroducers
 		}
-		if len(settingsToRestore.StaticPeers) > 0 {
-			staticPeers = settingsToRestore.StaticPeers
+		if len(profile.BootstrapPeers) > 0 {
+			bootstrapPeers = profile.BootstrapPeers
 		}
-		if len(settingsToRestore.FullNodePeers) > 0 {
-			fullnodePeers = settingsToRestore.FullNodePeers
+		if len(profile.StaticPeers) > 0 {
+			staticPeers = profile.StaticPeers
 		}
-		if len(settingsToRestore.WalletFullNodePeers) > 0 {
-			walletFullNodePeers = settingsToRestore.WalletFullNodePeers
+	}
+
+	// Any stored settings for the new network should be applied here, before any flags override them
+	if settingsToRestore != nil {
+		slogs.Logr.Info("restoring stored settings for this network")
+		for configPath, target := range map[string]any{
+			"full_node.dns_servers":     &dnsIntroducerHosts,
+			"seeder.bootstrap_peers":    &bootstrapPeers,
+			"seeder.static_peers":       &staticPeers,
+			"full_node.full_node_peers": &fullnodePeers,
+			"wallet.full_node_peers":    &walletFullNodePeers,
+		} {
+			err = restoreNonEmpty(settingsToRestore, configPath, target)
+			if err != nil {
+				slogs.Logr.Fatal("error restoring stored settings for the new network", "error", err)
+			}
 		}
 	}
 
@@ -221,53 +224,67 @@ func SwitchNetwork(networkName string, checkForRunningNode bool) {
 	if bootPeer := viper.GetString("switch-bootstrap-peer"); bootPeer != "" {
 		bootstrapPeers = []string{bootPeer}
 	}
-	// If there is a port in the config, use that, but still allow the flag to be the final say
+	// If there is a port in the config, use that, but still allow the profile and then the flag to be the final say
 	if netConfig.DefaultFullNodePort != 0 {
 		fullNodePort = netConfig.DefaultFullNodePort
 	}
+	if profile != nil && profile.Port != 0 {
+		fullNodePort = profile.Port
+	}
 	if portFlag := viper.GetUint16("switch-full-node-port"); portFlag != 0 {
 		fullNodePort = portFlag
 	}
 
-	pathUpdates := map[string]any{
-		"selected_network": networkName,
-		"farmer.full_node_peers": []config.Peer{
-			{
-				Host: "localhost",
-				Port: fullNodePort,
-			},
-		},
-		"full_node.database_path":        fmt.Sprintf("db/blockchain_v2_%s.sqlite", networkName),
-		"full_node.dns_servers":          dnsIntroducerHosts,
-		"full_node.peers_file_path":      peersFilePath,
-		"full_node.port":                 fullNodePort,
-		"full_node.full_node_peers":      fullnodePeers,
-		"full_node.introducer_peer.host": introducerHost,
-		"full_node.introducer_peer.port": fullNodePort,
-		"introducer.port":                fullNodePort,
-		"seeder.port":                    fullNodePort,
-		"seeder.other_peers_port":        fullNodePort,
-		"seeder.bootstrap_peers":         bootstrapPeers,
-		"seeder.static_peers":            staticPeers,
-		"timelord.full_node_peers": []config.Peer{
-			{
-				Host: "localhost",
-				Port: fullNodePort,
-			},
-		},
-		"wallet.dns_servers":            dnsIntroducerHosts,
-		"wallet.full_node_peers":        ensureAtLeastLocalPeer(walletFullNodePeers, fullNodePort),
-		"wallet.introducer_peer.host":   introducerHost,
-		"wallet.introducer_peer.port":   fullNodePort,
-		"wallet.wallet_peers_file_path": walletPeersFilePath,
+	pathUpdates := networkPathUpdates(networkName, networkSettings{
+		Port:                fullNodePort,
+		FullNodeHost:        "localhost",
+		IntroducerHost:      introducerHost,
+		DNSIntroducers:      dnsIntroducerHosts,
+		BootstrapPeers:      bootstrapPeers,
+		StaticPeers:         staticPeers,
+		FullNodePeers:       fullnodePeers,
+		WalletFullNodePeers: ensureAtLeastLocalPeer(walletFullNodePeers, fullNodePort),
+		PeersFilePath:       peersFilePath,
+		WalletPeersFilePath: walletPeersFilePath,
+	})
+	// Any other retained settings are restored as they were, unless the switch already sets the path
+	if settingsToRestore != nil {
+		for _, configPath := range retainedSettingPaths() {
+			if _, ok := pathUpdates[configPath]; ok {
+				continue
+			}
+			value, ok, err := settingsToRestore.typedValue(cfg, configPath)
+			if err != nil {
+				slogs.Logr.Warn("unable to restore stored setting, skipping", "path", configPath, "error", err)
+				continue
+			}
+			if ok {
+				pathUpdates[configPath] = value
+			}
+		}
+	}
+
+	configPaths := make([]string, 0, len(pathUpdates))
+	for configPath := range pathUpdates {
+		configPaths = append(configPaths, configPath)
 	}
-	for configPath, value := range pathUpdates {
+	sort.Strings(configPaths)
+
+	var configChanges []configChange
+	for _, configPath := range configPaths {
+		value := pathUpdates[configPath]
 		pathMap := config.ParsePathsFromStrings([]string{configPath}, false)
 		var key string
 		var pathSlice []string
 		for key, pathSlice = range pathMap {
 			break
 		}
+		currentValue, err := cfg.GetFieldByPath(pathSlice)
+		if err != nil {
+			slogs.Logr.Debug("config value not found", "path", configPath)
+		}
+		configChanges = append(configChanges, configChange{Path: configPath, Old: currentValue, New: value})
+
 		slogs.Logr.Debug("setting config path", "path", configPath, "value", value)
 		err = cfg.SetFieldByPath(pathSlice, value)
 		if err != nil {
@@ -275,20 +292,146 @@ func SwitchNetwork(networkName string, checkForRunningNode bool) {
 		}
 	}
 
-	slogs.Logr.Debug("saving config")
-	err = cfg.Save()
+	// Every change is staged and recorded in the journal before anything on disk is touched, so that a failure
+	// part way through can be reversed
+	journal := newSwitchJournal(chiaRoot, currentNetwork, networkName)
+	journal.addWrite("write retained settings for the current network", path.Join(cacheFileDirOldNetwork, settingsFileName), marshalledSettings)
+
+	activeSubEpochSummariesPath := path.Join(chiaRoot, "db", "sub-epoch-summaries")
+	activeHeightToHashPath := path.Join(chiaRoot, "db", "height-to-hash")
+
+	// Move current cache files to the network subdir
+	journal.addMove("store sub-epoch-summaries for the current network", activeSubEpochSummariesPath, path.Join(cacheFileDirOldNetwork, "sub-epoch-summaries"))
+	journal.addMove("store height-to-hash for the current network", activeHeightToHashPath, path.Join(cacheFileDirOldNetwork, "height-to-hash"))
+
+	// Move old cached files to active dir
+	journal.addMove("restore sub-epoch-summaries for the new network", path.Join(cacheFileDirNewNetwork, "sub-epoch-summaries"), activeSubEpochSummariesPath)
+	journal.addMove("restore height-to-hash for the new network", path.Join(cacheFileDirNewNetwork, "height-to-hash"), activeHeightToHashPath)
+
+	journal.addStaged("save chia config", path.Join(chiaRoot, "config", "config.yaml"), cfg.SavePath)
+
+	journal.addRemove("remove peers file for the new network", path.Join(chiaRoot, peersFilePath))
+
+	if viper.GetBool("dry-run") {
+		plan := newSwitchPlan(journal, configChanges)
+		err = plan.print(os.Stdout, chiaRoot, viper.GetBool("switch-as-json"))
+		if err != nil {
+			slogs.Logr.Fatal("error printing switch plan", "error", err)
+		}
+		return
+	}
+
+	slogs.Logr.Debug("ensuring directory exists for current network cache files", "directory", cacheFileDirOldNetwork)
+	err = os.MkdirAll(cacheFileDirOldNetwork, 0755)
 	if err != nil {
-		slogs.Logr.Fatal("error saving chia config", "error", err)
+		slogs.Logr.Fatal("error creating cache file directory for current network", "error", err, "directory", cacheFileDirOldNetwork)
 	}
 
-	err = removeFileIfExists(path.Join(chiaRoot, "db", peersFilePath))
+	slogs.Logr.Debug("ensuring directory exists for new network cache files", "directory", cacheFileDirNewNetwork)
+	err = os.MkdirAll(cacheFileDirNewNetwork, 0755)
 	if err != nil {
-		slogs.Logr.Error("error removing old peers.dat file", "path", peersFilePath, "error", err)
+		slogs.Logr.Fatal("error creating cache file directory for new network", "error", err, "directory", cacheFileDirNewNetwork)
+	}
+
+	// Check that services can be restarted before stopping them
+	if viper.GetBool("switch-restart") {
+		if _, err = chiaCommand(); err != nil {
+			slogs.Logr.Fatal("--restart can not be used. No files have been moved", "error", err)
+		}
+	}
+
+	// Make sure nothing is using the cache files before they are moved
+	var runningServices []rpc.ServiceFullName
+	if checkForRunningNode {
+		runningServices, err = stopChiaServices(cfg, viper.GetDuration("switch-stop-timeout"))
+		if err != nil {
+			slogs.Logr.Fatal("error stopping chia services. No files have been moved", "error", err)
+		}
+	}
+
+	_, err = backups.Create(path.Join(chiaRoot, "config", "config.yaml"), "network switch", viper.GetInt("config-backups"))
+	if err != nil {
+		slogs.Logr.Fatal("error backing up config. No files have been moved", "error", err)
+	}
+
+	err = journal.run()
+	if err != nil {
+		slogs.Logr.Fatal("error switching networks. Any completed steps have been reverted", "error", err)
+	}
+
+	if viper.GetBool("switch-restart") && len(runningServices) > 0 {
+		err = startChiaServices(cfg, runningServices, viper.GetDuration("switch-stop-timeout"))
+		if err != nil {
+			slogs.Logr.Fatal("network switched, but chia services could not be restarted", "error", err)
+		}
 	}
 
 	slogs.Logr.Info("Complete")
 }
 
+// networkSettings are the values that point a chia config at a network
+type networkSettings struct {
+	// Port is the network's full node port, which the introducer and seeder also use
+	Port uint16
+	// FullNodeHost is the full node the farmer and timelord connect to
+	FullNodeHost        string
+	IntroducerHost      string
+	DNSIntroducers      []string
+	BootstrapPeers      []string
+	StaticPeers         []string
+	FullNodePeers       []config.Peer
+	WalletFullNodePeers []config.Peer
+	PeersFilePath       string
+	WalletPeersFilePath string
+}
+
+// networkPathUpdates returns the config paths, and their values, that select networkName with the settings. Used by
+// network switch for an existing config, and by network generate --bundle for new ones.
+func networkPathUpdates(networkName string, settings networkSettings) map[string]any {
+	fullNodePeers := []config.Peer{
+		{
+			Host: settings.FullNodeHost,
+			Port: settings.Port,
+		},
+	}
+	return map[string]any{
+		"selected_network":               networkName,
+		"farmer.full_node_peers":         fullNodePeers,
+		"full_node.database_path":        fmt.Sprintf("db/blockchain_v2_%s.sqlite", networkName),
+		"full_node.dns_servers":          settings.DNSIntroducers,
+		"full_node.peers_file_path":      settings.PeersFilePath,
+		"full_node.port":                 settings.Port,
+		"full_node.full_node_peers":      settings.FullNodePeers,
+		"full_node.introducer_peer.host": settings.IntroducerHost,
+		"full_node.introducer_peer.port": settings.Port,
+		"introducer.port":                settings.Port,
+		"seeder.port":                    settings.Port,
+		"seeder.other_peers_port":        settings.Port,
+		"seeder.bootstrap_peers":         settings.BootstrapPeers,
+		"seeder.static_peers":            settings.StaticPeers,
+		"timelord.full_node_peers":       fullNodePeers,
+		"wallet.dns_servers":             settings.DNSIntroducers,
+		"wallet.full_node_peers":         settings.WalletFullNodePeers,
+		"wallet.introducer_peer.host":    settings.IntroducerHost,
+		"wallet.introducer_peer.port":    settings.Port,
+		"wallet.wallet_peers_file_path":  settings.WalletPeersFilePath,
+	}
+}
+
+// restoreNonEmpty replaces target with the retained value of configPath, if one was retained and it is not empty.
+// target must be a pointer to a slice.
+func restoreNonEmpty(settings *retainedSettings, configPath string, target any) error {
+	restored := reflect.New(reflect.TypeOf(target).Elem())
+	ok, err := settings.decode(configPath, restored.Interface())
+	if err != nil {
+		return err
+	}
+	if ok && restored.Elem().Len() > 0 {
+		reflect.ValueOf(target).Elem().Set(restored.Elem())
+	}
+	return nil
+}
+
 func ensureAtLeastLocalPeer(peers []config.Peer, port uint16) []config.Peer {
 	if len(peers) == 0 {
 		peers = append(peers, config.Peer{
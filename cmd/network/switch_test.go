@@ -7,6 +7,7 @@ import (
 
 	"github.com/chia-network/go-chia-libs/pkg/config"
 	"github.com/chia-network/go-chia-libs/pkg/types"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/chia-network/chia-tools/cmd"
@@ -128,3 +129,131 @@ func TestNetworkSwitch_SettingRetention(t *testing.T) {
 	assert.Equal(t, []string{"static-peer-1.example.com"}, cfg.Seeder.StaticPeers)
 	assert.Equal(t, []config.Peer{{Host: "fn-peer-1.example.com", Port: 1234}}, cfg.FullNode.FullNodePeers)
 }
+
+func TestNetworkSwitch_DryRun(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+	viper.Set("dry-run", true)
+	defer viper.Set("dry-run", false)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	heightToHash := filepath.Join(rootPath, "db", "height-to-hash")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(heightToHash), 0755))
+	assert.NoError(t, os.WriteFile(heightToHash, []byte("mainnet"), 0644))
+
+	network.SwitchNetwork("unittestnet", false)
+
+	// Nothing on disk should have changed
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "mainnet", *cfg.SelectedNetwork)
+	assert.FileExists(t, heightToHash)
+	assert.NoDirExists(t, filepath.Join(rootPath, "db", "mainnet"))
+	assert.NoDirExists(t, filepath.Join(rootPath, "db", "unittestnet"))
+}
+
+func TestNetworkSwitch_Profile(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	profileDir := filepath.Join(rootPath, "db", testnetwork)
+	assert.NoError(t, os.MkdirAll(profileDir, 0755))
+	profile := `introducer: introducer.example.com
+dns_introducers:
+  - dns-1.example.com
+  - dns-2.example.com
+bootstrap_peers:
+  - bootstrap.example.com
+static_peers:
+  - static.example.com
+port: 1234
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(profileDir, "profile.yaml"), []byte(profile), 0644))
+
+	// Values in the chia-tools config take precedence over the stored profile
+	viper.Set("network-profiles", map[string]any{
+		testnetwork: map[string]any{"port": 4321},
+	})
+	defer viper.Set("network-profiles", nil)
+
+	network.SwitchNetwork(testnetwork, false)
+
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+
+	port := uint16(4321)
+	assert.Equal(t, testnetwork, *cfg.SelectedNetwork)
+	assert.Equal(t, []string{"dns-1.example.com", "dns-2.example.com"}, cfg.FullNode.DNSServers)
+	assert.Equal(t, []string{"dns-1.example.com", "dns-2.example.com"}, cfg.Wallet.DNSServers)
+	assert.Equal(t, config.Peer{Host: "introducer.example.com", Port: port}, cfg.FullNode.IntroducerPeer)
+	assert.Equal(t, config.Peer{Host: "introducer.example.com", Port: port}, cfg.Wallet.IntroducerPeer)
+	assert.Equal(t, []string{"bootstrap.example.com"}, cfg.Seeder.BootstrapPeers)
+	assert.Equal(t, []string{"static.example.com"}, cfg.Seeder.StaticPeers)
+	assert.Equal(t, port, cfg.FullNode.Port)
+}
+
+func TestNetworkSwitch_ConfiguredRetainedSettings(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+	viper.Set("retained-settings", []string{"full_node.target_peer_count", "logging.log_level"})
+	defer viper.Set("retained-settings", nil)
+
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	cfg.FullNode.TargetPeerCount = 100
+	cfg.Logging.LogLevel = "DEBUG"
+	assert.NoError(t, cfg.Save())
+
+	network.SwitchNetwork(testnetwork, false)
+
+	// The testnet gets its own values, which should not carry back to mainnet
+	cfg, err = config.GetChiaConfig()
+	assert.NoError(t, err)
+	cfg.FullNode.TargetPeerCount = 20
+	cfg.Logging.LogLevel = "INFO"
+	assert.NoError(t, cfg.Save())
+
+	network.SwitchNetwork("mainnet", false)
+
+	cfg, err = config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(100), cfg.FullNode.TargetPeerCount)
+	assert.Equal(t, "DEBUG", cfg.Logging.LogLevel)
+
+	network.SwitchNetwork(testnetwork, false)
+
+	cfg, err = config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(20), cfg.FullNode.TargetPeerCount)
+	assert.Equal(t, "INFO", cfg.Logging.LogLevel)
+}
+
+func TestNetworkSwitch_LegacyRetainedSettings(t *testing.T) {
+	cmd.InitLogs()
+	setupDefaultConfig(t)
+
+	// settings.json written before settings were keyed by config path
+	rootPath, err := config.GetChiaRootPath()
+	assert.NoError(t, err)
+	settingsDir := filepath.Join(rootPath, "db", testnetwork)
+	assert.NoError(t, os.MkdirAll(settingsDir, 0755))
+	legacy := `{"dns_servers":["dns.example.com"],"bootstrap_peers":null,"static_peers":["static.example.com"],"full_node_peers":[{"host":"peer.example.com","port":1234}],"wallet_full_node_peers":null}`
+	assert.NoError(t, os.WriteFile(filepath.Join(settingsDir, "settings.json"), []byte(legacy), 0644))
+
+	network.SwitchNetwork(testnetwork, false)
+
+	cfg, err := config.GetChiaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dns.example.com"}, cfg.FullNode.DNSServers)
+	assert.Equal(t, []string{"node-unittestnet.chia.net"}, cfg.Seeder.BootstrapPeers)
+	assert.Equal(t, []string{"static.example.com"}, cfg.Seeder.StaticPeers)
+	assert.Equal(t, []config.Peer{{Host: "peer.example.com", Port: 1234}}, cfg.FullNode.FullNodePeers)
+
+	// Settings stored for mainnet are in the current format
+	stored, err := os.ReadFile(filepath.Join(rootPath, "db", "mainnet", "settings.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(stored), `"version":2`)
+}
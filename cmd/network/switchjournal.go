@@ -0,0 +1,467 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+)
+
+const (
+	journalFileName = "network-switch-journal.json"
+	stagedSuffix    = ".switch-staged"
+	backupSuffix    = ".switch-backup"
+)
+
+type stepAction string
+
+const (
+	// stepActionMove moves Source to Dest, keeping any existing Dest at Backup until the switch is committed
+	stepActionMove stepAction = "move"
+	// stepActionRemove moves Source to Backup, and deletes it once the switch is committed
+	stepActionRemove stepAction = "remove"
+)
+
+type stepStatus string
+
+const (
+	stepStatusPending stepStatus = "pending"
+	stepStatusStarted stepStatus = "started"
+	stepStatusDone    stepStatus = "done"
+	stepStatusSkipped stepStatus = "skipped"
+)
+
+// journalStep is a single reversible file operation that is part of a network switch
+type journalStep struct {
+	Action      stepAction `json:"action"`
+	Description string     `json:"description"`
+	Source      string     `json:"source"`
+	Dest        string     `json:"dest,omitempty"`
+	Backup      string     `json:"backup"`
+	// Staged is true when Source was written by chia-tools while planning the switch, rather than being an existing file
+	Staged bool       `json:"staged,omitempty"`
+	Status stepStatus `json:"status"`
+
+	// stage creates the staged source file. Only set for journals that were planned in this process.
+	stage func(staged string) error
+}
+
+// switchJournal records every planned step of a network switch before any of them are executed, so that a
+// switch that fails or is interrupted can be reversed or completed later
+type switchJournal struct {
+	path  string
+	From  string         `json:"from"`
+	To    string         `json:"to"`
+	Steps []*journalStep `json:"steps"`
+}
+
+func journalPath(chiaRoot string) string {
+	return path.Join(chiaRoot, "db", journalFileName)
+}
+
+func newSwitchJournal(chiaRoot, from, to string) *switchJournal {
+	return &switchJournal{
+		path: journalPath(chiaRoot),
+		From: from,
+		To:   to,
+	}
+}
+
+// loadSwitchJournal loads the journal for an incomplete switch. Returns nil if no switch is in progress.
+func loadSwitchJournal(chiaRoot string) (*switchJournal, error) {
+	journalFile := journalPath(chiaRoot)
+	data, err := os.ReadFile(journalFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading switch journal: %w", err)
+	}
+
+	journal := &switchJournal{path: journalFile}
+	err = json.Unmarshal(data, journal)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling switch journal: %w", err)
+	}
+
+	return journal, nil
+}
+
+// addMove adds a step that moves an existing file into a new location
+func (j *switchJournal) addMove(description, source, dest string) {
+	j.Steps = append(j.Steps, &journalStep{
+		Action:      stepActionMove,
+		Description: description,
+		Source:      source,
+		Dest:        dest,
+		Backup:      dest + backupSuffix,
+		Status:      stepStatusPending,
+	})
+}
+
+// addWrite adds a step that writes contents to dest. The contents are staged next to dest when the journal runs
+// and then moved into place.
+func (j *switchJournal) addWrite(description, dest string, contents []byte) {
+	j.addStaged(description, dest, func(staged string) error {
+		return os.WriteFile(staged, contents, 0644)
+	})
+}
+
+// addStaged adds a step that moves a file created by stage into place at dest
+func (j *switchJournal) addStaged(description, dest string, stage func(staged string) error) {
+	j.Steps = append(j.Steps, &journalStep{
+		Action:      stepActionMove,
+		Description: description,
+		Source:      dest + stagedSuffix,
+		Dest:        dest,
+		Backup:      dest + backupSuffix,
+		Staged:      true,
+		Status:      stepStatusPending,
+		stage:       stage,
+	})
+}
+
+// addRemove adds a step that removes a file
+func (j *switchJournal) addRemove(description, source string) {
+	j.Steps = append(j.Steps, &journalStep{
+		Action:      stepActionRemove,
+		Description: description,
+		Source:      source,
+		Backup:      source + backupSuffix,
+		Status:      stepStatusPending,
+	})
+}
+
+func (j *switchJournal) save() error {
+	marshalled, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling switch journal: %w", err)
+	}
+	tmpPath := j.path + ".tmp"
+	err = os.WriteFile(tmpPath, marshalled, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing switch journal: %w", err)
+	}
+	err = os.Rename(tmpPath, j.path)
+	if err != nil {
+		return fmt.Errorf("error writing switch journal: %w", err)
+	}
+	return nil
+}
+
+// run persists the journal and executes all pending steps. If a step fails, all completed steps are reversed.
+func (j *switchJournal) run() error {
+	for _, step := range j.Steps {
+		if step.stage == nil || step.Status != stepStatusPending {
+			continue
+		}
+		slogs.Logr.Debug("staging file", "path", step.Source)
+		err := step.stage(step.Source)
+		if err != nil {
+			_ = j.rollback()
+			return fmt.Errorf("error staging %s: %w", step.Dest, err)
+		}
+	}
+
+	err := j.save()
+	if err != nil {
+		_ = j.rollback()
+		return err
+	}
+
+	for _, step := range j.Steps {
+		if step.Status == stepStatusDone || step.Status == stepStatusSkipped {
+			continue
+		}
+		slogs.Logr.Debug("executing switch step", "step", step.Description)
+		err = j.execute(step)
+		if err != nil {
+			slogs.Logr.Error("network switch step failed, rolling back", "step", step.Description, "error", err)
+			rollbackErr := j.rollback()
+			if rollbackErr != nil {
+				return fmt.Errorf("%s: %w (rollback also failed: %s)", step.Description, err, rollbackErr.Error())
+			}
+			return fmt.Errorf("%s: %w", step.Description, err)
+		}
+	}
+
+	return j.commit()
+}
+
+func (j *switchJournal) execute(step *journalStep) error {
+	// A missing source is recorded as skipped before anything is touched, so that a started step always had a source
+	// to move, and rollback never reverses a move that didn't happen
+	if step.Status == stepStatusPending {
+		if _, err := os.Stat(step.Source); err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("error checking source file: %w", err)
+			}
+			if step.Staged {
+				return fmt.Errorf("staged file is missing: %s", step.Source)
+			}
+			slogs.Logr.Debug("source path doesn't exist, skipping", "source", step.Source)
+			step.Status = stepStatusSkipped
+			return j.save()
+		}
+	}
+
+	wasStarted := step.Status == stepStatusStarted
+	step.Status = stepStatusStarted
+	err := j.save()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(step.Source); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error checking source file: %w", err)
+		}
+		if !wasStarted {
+			return fmt.Errorf("source file is missing: %s", step.Source)
+		}
+		// We were interrupted after this step already moved the source
+		step.Status = stepStatusDone
+		return j.save()
+	}
+
+	switch step.Action {
+	case stepActionMove:
+		if _, err := os.Stat(step.Dest); err == nil {
+			if _, err := os.Stat(step.Backup); err == nil {
+				err = os.Remove(step.Dest)
+				if err != nil {
+					return fmt.Errorf("error removing destination file: %w", err)
+				}
+			} else {
+				slogs.Logr.Debug("backing up existing destination file", "dest", step.Dest, "backup", step.Backup)
+				err = os.Rename(step.Dest, step.Backup)
+				if err != nil {
+					return fmt.Errorf("error backing up destination file: %w", err)
+				}
+			}
+		}
+		slogs.Logr.Debug("moving file to destination", "source", step.Source, "dest", step.Dest)
+		err = os.Rename(step.Source, step.Dest)
+		if err != nil {
+			return fmt.Errorf("error moving file: %w", err)
+		}
+	case stepActionRemove:
+		slogs.Logr.Debug("removing file", "path", step.Source)
+		err = os.Rename(step.Source, step.Backup)
+		if err != nil {
+			return fmt.Errorf("error removing file: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown switch step action %q", step.Action)
+	}
+
+	step.Status = stepStatusDone
+	return j.save()
+}
+
+// rollback reverses every started or completed step, newest first, and then removes the journal
+func (j *switchJournal) rollback() error {
+	var errs []error
+	for i := len(j.Steps) - 1; i >= 0; i-- {
+		step := j.Steps[i]
+		if step.Status != stepStatusDone && step.Status != stepStatusStarted {
+			continue
+		}
+		slogs.Logr.Info("reverting switch step", "step", step.Description)
+		err := step.undo()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Description, err))
+			continue
+		}
+		step.Status = stepStatusPending
+	}
+
+	for _, step := range j.Steps {
+		if step.Staged {
+			err := removeFileIfExists(step.Source)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		// Keep the journal around so rollback can be attempted again
+		if err := j.save(); err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+
+	return removeFileIfExists(j.path)
+}
+
+func (s *journalStep) undo() error {
+	moved, err := s.moved()
+	if err != nil {
+		return err
+	}
+
+	switch s.Action {
+	case stepActionMove:
+		if moved {
+			if s.Staged {
+				err = removeFileIfExists(s.Dest)
+			} else {
+				err = moveAndOverwriteFile(s.Dest, s.Source)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// The existing destination may have been backed up even if the move itself didn't happen
+		return s.restoreBackup(s.Dest)
+	case stepActionRemove:
+		if !moved {
+			return nil
+		}
+		return s.restoreBackup(s.Source)
+	default:
+		return fmt.Errorf("unknown switch step action %q", s.Action)
+	}
+}
+
+// moved returns whether the step's file operation happened. A step that was interrupted may not have done anything
+// yet: its source is only gone once it was moved, and a moved file is only in place once its destination exists.
+func (s *journalStep) moved() (bool, error) {
+	if s.Status != stepStatusStarted {
+		return true, nil
+	}
+	_, err := os.Stat(s.Source)
+	if err == nil {
+		return false, nil
+	}
+	if !os.IsNotExist(err) {
+		return false, fmt.Errorf("error checking source file: %w", err)
+	}
+	if s.Action != stepActionMove {
+		return true, nil
+	}
+	_, err = os.Stat(s.Dest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking destination file: %w", err)
+	}
+	return true, nil
+}
+
+// restoreBackup moves the backup kept by the step back to path. Nothing is restored if there is no backup, since the
+// file at path did not exist before the step.
+func (s *journalStep) restoreBackup(path string) error {
+	if _, err := os.Stat(s.Backup); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error checking backup file: %w", err)
+	}
+	return moveAndOverwriteFile(s.Backup, path)
+}
+
+// commit removes the backups kept for rollback and the journal itself
+func (j *switchJournal) commit() error {
+	for _, step := range j.Steps {
+		err := removeFileIfExists(step.Backup)
+		if err != nil {
+			return fmt.Errorf("error removing backup file: %w", err)
+		}
+	}
+
+	return removeFileIfExists(j.path)
+}
+
+// ResumeNetworkSwitch completes a network switch that was interrupted
+func ResumeNetworkSwitch(checkForRunningNode bool) {
+	finishNetworkSwitch(checkForRunningNode, false)
+}
+
+// RollbackNetworkSwitch reverts a network switch that was interrupted
+func RollbackNetworkSwitch(checkForRunningNode bool) {
+	finishNetworkSwitch(checkForRunningNode, true)
+}
+
+// finishNetworkSwitch completes or reverts an interrupted network switch the same way a new switch runs: nothing is
+// changed for --dry-run, and chia services are stopped before any files are moved
+func finishNetworkSwitch(checkForRunningNode, rollback bool) {
+	chiaRoot, err := config.GetChiaRootPath()
+	if err != nil {
+		slogs.Logr.Fatal("error determining chia root", "error", err)
+	}
+
+	journal, err := loadSwitchJournal(chiaRoot)
+	if err != nil {
+		slogs.Logr.Fatal("error loading switch journal", "error", err)
+	}
+	if journal == nil {
+		slogs.Logr.Fatal("no incomplete network switch found", "journal", journalPath(chiaRoot))
+	}
+
+	if viper.GetBool("dry-run") {
+		for _, step := range journal.Steps {
+			switch {
+			case rollback && (step.Status == stepStatusDone || step.Status == stepStatusStarted):
+				slogs.Logr.Info("DRY RUN: Would revert switch step", "step", step.Description, "status", step.Status)
+			case !rollback && step.Status != stepStatusDone && step.Status != stepStatusSkipped:
+				slogs.Logr.Info("DRY RUN: Would complete switch step", "step", step.Description, "status", step.Status)
+			}
+		}
+		slogs.Logr.Info("DRY RUN: No changes were made", "from", journal.From, "to", journal.To)
+		return
+	}
+
+	cfg, err := config.GetChiaConfig()
+	if err != nil {
+		slogs.Logr.Fatal("error loading config", "error", err)
+	}
+
+	// Check that services can be restarted before stopping them
+	if viper.GetBool("switch-restart") {
+		if _, err = chiaCommand(); err != nil {
+			slogs.Logr.Fatal("--restart can not be used. No files have been moved", "error", err)
+		}
+	}
+
+	// Make sure nothing is using the cache files before they are moved
+	var runningServices []rpc.ServiceFullName
+	if checkForRunningNode {
+		runningServices, err = stopChiaServices(cfg, viper.GetDuration("switch-stop-timeout"))
+		if err != nil {
+			slogs.Logr.Fatal("error stopping chia services. No files have been moved", "error", err)
+		}
+	}
+
+	if rollback {
+		slogs.Logr.Info("Rolling back network switch", "from", journal.From, "to", journal.To)
+		err = journal.rollback()
+		if err != nil {
+			slogs.Logr.Fatal("error rolling back network switch", "error", err)
+		}
+	} else {
+		slogs.Logr.Info("Resuming network switch", "from", journal.From, "to", journal.To)
+		err = journal.run()
+		if err != nil {
+			slogs.Logr.Fatal("error resuming network switch. Any completed steps have been reverted", "error", err)
+		}
+	}
+
+	if viper.GetBool("switch-restart") && len(runningServices) > 0 {
+		err = startChiaServices(cfg, runningServices, viper.GetDuration("switch-stop-timeout"))
+		if err != nil {
+			slogs.Logr.Fatal("network switch finished, but chia services could not be restarted", "error", err)
+		}
+	}
+
+	slogs.Logr.Info("Complete")
+}
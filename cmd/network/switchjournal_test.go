@@ -0,0 +1,195 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chia-network/chia-tools/cmd"
+)
+
+func writeTestFile(t *testing.T, path, contents string) {
+	err := os.WriteFile(path, []byte(contents), 0644)
+	assert.NoError(t, err)
+}
+
+func assertFileContents(t *testing.T, path, contents string) {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, contents, string(data))
+}
+
+func TestSwitchJournal_RollbackOnFailure(t *testing.T) {
+	cmd.InitLogs()
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "db"), 0755))
+
+	active := filepath.Join(root, "db", "height-to-hash")
+	stored := filepath.Join(root, "db", "stored-height-to-hash")
+	settings := filepath.Join(root, "db", "settings.json")
+	writeTestFile(t, active, "active")
+	writeTestFile(t, stored, "stored")
+	writeTestFile(t, settings, "old settings")
+
+	journal := newSwitchJournal(root, "mainnet", "testnet")
+	journal.addWrite("write settings", settings, []byte("new settings"))
+	journal.addMove("move active file", active, stored)
+	// The destination directory doesn't exist, so this step fails
+	journal.addMove("move to missing dir", stored, filepath.Join(root, "missing", "height-to-hash"))
+
+	err := journal.run()
+	assert.Error(t, err)
+
+	assertFileContents(t, active, "active")
+	assertFileContents(t, stored, "stored")
+	assertFileContents(t, settings, "old settings")
+	assert.NoFileExists(t, settings+stagedSuffix)
+	assert.NoFileExists(t, stored+backupSuffix)
+	assert.NoFileExists(t, journalPath(root))
+}
+
+func TestSwitchJournal_ResumeAndRollback(t *testing.T) {
+	cmd.InitLogs()
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "db"), 0755))
+
+	active := filepath.Join(root, "db", "height-to-hash")
+	stored := filepath.Join(root, "db", "stored-height-to-hash")
+	peers := filepath.Join(root, "db", "peers.dat")
+	writeTestFile(t, active, "active")
+	writeTestFile(t, stored, "stored")
+	writeTestFile(t, peers, "peers")
+
+	newJournal := func() *switchJournal {
+		journal := newSwitchJournal(root, "mainnet", "testnet")
+		journal.addMove("move active file", active, stored)
+		journal.addRemove("remove peers", peers)
+		return journal
+	}
+
+	// Simulate being interrupted after the first step
+	journal := newJournal()
+	assert.NoError(t, journal.save())
+	assert.NoError(t, journal.execute(journal.Steps[0]))
+
+	loaded, err := loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.Equal(t, stepStatusDone, loaded.Steps[0].Status)
+	assert.Equal(t, stepStatusPending, loaded.Steps[1].Status)
+
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assertFileContents(t, stored, "stored")
+	assertFileContents(t, peers, "peers")
+	assert.NoFileExists(t, journalPath(root))
+
+	// Interrupt again, and this time resume
+	journal = newJournal()
+	assert.NoError(t, journal.save())
+	assert.NoError(t, journal.execute(journal.Steps[0]))
+
+	loaded, err = loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.run())
+	assert.NoFileExists(t, active)
+	assertFileContents(t, stored, "active")
+	assert.NoFileExists(t, peers)
+	assert.NoFileExists(t, stored+backupSuffix)
+	assert.NoFileExists(t, peers+backupSuffix)
+	assert.NoFileExists(t, journalPath(root))
+}
+
+func TestSwitchJournal_RollbackStartedStep(t *testing.T) {
+	cmd.InitLogs()
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "db"), 0755))
+
+	active := filepath.Join(root, "db", "height-to-hash")
+	stored := filepath.Join(root, "db", "stored-height-to-hash")
+	peers := filepath.Join(root, "db", "peers.dat")
+	writeTestFile(t, active, "active")
+	writeTestFile(t, stored, "stored")
+	writeTestFile(t, peers, "peers")
+
+	// Simulate being interrupted after the existing destination was backed up, but before the source was moved
+	journal := newSwitchJournal(root, "mainnet", "testnet")
+	journal.addMove("move active file", active, stored)
+	journal.addRemove("remove peers", peers)
+	journal.Steps[0].Status = stepStatusStarted
+	journal.Steps[1].Status = stepStatusStarted
+	assert.NoError(t, os.Rename(stored, stored+backupSuffix))
+	assert.NoError(t, journal.save())
+
+	loaded, err := loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assertFileContents(t, stored, "stored")
+	assertFileContents(t, peers, "peers")
+	assert.NoFileExists(t, stored+backupSuffix)
+	assert.NoFileExists(t, journalPath(root))
+
+	// Interrupted before anything was touched, the source must not be replaced by the destination
+	journal = newSwitchJournal(root, "mainnet", "testnet")
+	journal.addMove("move active file", active, stored)
+	journal.Steps[0].Status = stepStatusStarted
+	assert.NoError(t, journal.save())
+
+	loaded, err = loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assertFileContents(t, stored, "stored")
+
+	// Interrupted after the source was moved, the move is reversed
+	journal = newSwitchJournal(root, "mainnet", "testnet")
+	journal.addMove("move active file", active, stored)
+	journal.Steps[0].Status = stepStatusStarted
+	assert.NoError(t, os.Rename(stored, stored+backupSuffix))
+	assert.NoError(t, os.Rename(active, stored))
+	assert.NoError(t, journal.save())
+
+	loaded, err = loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assertFileContents(t, stored, "stored")
+	assert.NoFileExists(t, stored+backupSuffix)
+}
+
+func TestSwitchJournal_MissingSource(t *testing.T) {
+	cmd.InitLogs()
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "db"), 0755))
+
+	active := filepath.Join(root, "db", "height-to-hash")
+	stored := filepath.Join(root, "db", "stored-height-to-hash")
+	writeTestFile(t, active, "active")
+
+	// A missing source is skipped without the step ever being recorded as started
+	journal := newSwitchJournal(root, "mainnet", "testnet")
+	journal.addMove("move stored file", stored, active)
+	assert.NoError(t, journal.save())
+	assert.NoError(t, journal.execute(journal.Steps[0]))
+	assert.Equal(t, stepStatusSkipped, journal.Steps[0].Status)
+	loaded, err := loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.Equal(t, stepStatusSkipped, loaded.Steps[0].Status)
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assert.NoFileExists(t, stored)
+
+	// A started step whose source and destination are both missing moved nothing, so nothing is reversed
+	journal = newSwitchJournal(root, "mainnet", "testnet")
+	journal.addMove("move stored file", stored, filepath.Join(root, "db", "missing"))
+	journal.Steps[0].Status = stepStatusStarted
+	assert.NoError(t, journal.save())
+	loaded, err = loadSwitchJournal(root)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.rollback())
+	assertFileContents(t, active, "active")
+	assert.NoFileExists(t, stored)
+	assert.NoFileExists(t, journalPath(root))
+}
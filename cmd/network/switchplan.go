@@ -0,0 +1,148 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"text/tabwriter"
+)
+
+// configChange is a single config path that a network switch updates
+type configChange struct {
+	Path    string `json:"path"`
+	Old     any    `json:"old"`
+	New     any    `json:"new"`
+	Changed bool   `json:"changed"`
+}
+
+// fileOperation is a single file operation that a network switch performs
+type fileOperation struct {
+	Action      string `json:"action"`
+	Description string `json:"description"`
+	Source      string `json:"source,omitempty"`
+	Dest        string `json:"dest,omitempty"`
+	// Skipped is true when the source file does not exist, so there is nothing to move or remove
+	Skipped bool `json:"skipped"`
+}
+
+// switchPlan describes everything a network switch would change, without changing anything
+type switchPlan struct {
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	ConfigChanges  []configChange  `json:"config_changes"`
+	FileOperations []fileOperation `json:"file_operations"`
+}
+
+func newSwitchPlan(journal *switchJournal, changes []configChange) *switchPlan {
+	plan := &switchPlan{
+		From:           journal.From,
+		To:             journal.To,
+		ConfigChanges:  make([]configChange, 0, len(changes)),
+		FileOperations: make([]fileOperation, 0, len(journal.Steps)),
+	}
+
+	for _, change := range changes {
+		change.Changed = !valuesEqual(change.Old, change.New)
+		plan.ConfigChanges = append(plan.ConfigChanges, change)
+	}
+
+	for _, step := range journal.Steps {
+		op := fileOperation{
+			Action:      string(step.Action),
+			Description: step.Description,
+			Source:      step.Source,
+			Dest:        step.Dest,
+		}
+		if step.Staged {
+			op.Action = "write"
+			op.Source = ""
+		} else if _, err := os.Stat(step.Source); err != nil {
+			op.Skipped = true
+		}
+		plan.FileOperations = append(plan.FileOperations, op)
+	}
+
+	return plan
+}
+
+// valuesEqual compares config values by their JSON representation, since the value read from the config and the
+// value being set may be different types with the same contents (a nil slice and an empty slice, for example)
+func valuesEqual(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	normalize := func(value []byte) string {
+		if string(value) == "[]" {
+			return "null"
+		}
+		return string(value)
+	}
+	return normalize(aJSON) == normalize(bJSON)
+}
+
+func (p *switchPlan) print(w io.Writer, chiaRoot string, asJSON bool) error {
+	if asJSON {
+		marshalled, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling switch plan: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(marshalled))
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "DRY RUN: Switching from %s to %s would make the following changes\n", p.From, p.To)
+
+	_, _ = fmt.Fprintln(w, "\nConfig Changes")
+	tw := tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	for _, change := range p.ConfigChanges {
+		if !change.Changed {
+			_, _ = fmt.Fprintf(tw, "  %s\t %s\t (unchanged)\n", change.Path, planValue(change.Old))
+			continue
+		}
+		_, _ = fmt.Fprintf(tw, "  %s\t %s\t -> %s\n", change.Path, planValue(change.Old), planValue(change.New))
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintln(w, "\nFile Operations")
+	tw = tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	for _, op := range p.FileOperations {
+		var target string
+		switch op.Action {
+		case "write":
+			target = relativeToRoot(chiaRoot, op.Dest)
+		case string(stepActionRemove):
+			target = relativeToRoot(chiaRoot, op.Source)
+		default:
+			target = fmt.Sprintf("%s -> %s", relativeToRoot(chiaRoot, op.Source), relativeToRoot(chiaRoot, op.Dest))
+		}
+		if op.Skipped {
+			target = fmt.Sprintf("%s (skipped, source does not exist)", target)
+		}
+		_, _ = fmt.Fprintf(tw, "  %s\t %s\t %s\n", op.Action, target, op.Description)
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintln(w, "\nDRY RUN: No changes were made")
+	return nil
+}
+
+func planValue(value any) string {
+	marshalled, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(marshalled)
+}
+
+func relativeToRoot(chiaRoot, path string) string {
+	rel, err := filepath.Rel(chiaRoot, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,198 @@
+package network
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	minSaneMinPlotSize = 18
+	maxSaneMinPlotSize = 50
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Checks network constants for internal consistency",
+	Example: `# Validate a network in the local config
+chia-tools network validate mytestnet
+
+# Validate every network in a file, such as the output of network generate --with-constants
+chia-tools network validate my-network-config.yml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		networks := map[string]config.NetworkConstants{}
+
+		if _, err := os.Stat(args[0]); err == nil {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				slogs.Logr.Fatal("error reading file", "error", err)
+			}
+			definition, err := parseNetworkDefinition(data)
+			if err != nil {
+				slogs.Logr.Fatal("error parsing network definition", "error", err)
+			}
+			networks = definition.NetworkOverrides.Constants
+		} else {
+			cfg, err := config.GetChiaConfig()
+			if err != nil {
+				slogs.Logr.Fatal("error loading config", "error", err)
+			}
+			hasConstants, _ := hasNetwork(cfg, args[0])
+			if !hasConstants {
+				slogs.Logr.Fatal("network is not a file, and does not exist in config's network override constants", "network", args[0])
+			}
+			networks[args[0]] = cfg.NetworkOverrides.Constants[args[0]]
+		}
+
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		valid := true
+		for _, name := range names {
+			if !logValidationErrors(name, validateNetworkConstants(networks[name])) {
+				valid = false
+				continue
+			}
+			slogs.Logr.Info("Network constants are valid", "network", name)
+		}
+		if !valid {
+			os.Exit(1)
+		}
+	},
+}
+
+// validateNetworkConstants checks network constants for internal consistency, and returns every problem found.
+// Network constants in the config only override chia's defaults, so only the fields that are set are checked, other
+// than the genesis values every network must define.
+func validateNetworkConstants(constants config.NetworkConstants) []error {
+	var errs []error
+
+	for _, field := range []struct {
+		name     string
+		value    string
+		required bool
+	}{
+		{"AGG_SIG_ME_ADDITIONAL_DATA", constants.AggSigMeAdditionalData, false},
+		{"GENESIS_CHALLENGE", constants.GenesisChallenge, true},
+		{"GENESIS_PRE_FARM_POOL_PUZZLE_HASH", constants.GenesisPreFarmPoolPuzzleHash, true},
+		{"GENESIS_PRE_FARM_FARMER_PUZZLE_HASH", constants.GenesisPreFarmFarmerPuzzleHash, true},
+	} {
+		if field.value == "" && !field.required {
+			continue
+		}
+		if err := validateBytes32(field.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s %w", field.name, err))
+		}
+	}
+
+	if constants.MinPlotSize != 0 && (constants.MinPlotSize < minSaneMinPlotSize || constants.MinPlotSize > maxSaneMinPlotSize) {
+		errs = append(errs, fmt.Errorf("MIN_PLOT_SIZE must be between %d and %d, got %d", minSaneMinPlotSize, maxSaneMinPlotSize, constants.MinPlotSize))
+	}
+
+	// Forks must activate in order
+	if err := validateHeightOrder("HARD_FORK_HEIGHT", constants.HardForkHeight, "HARD_FORK2_HEIGHT", constants.HardFork2Height); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateHeightOrder("SOFT_FORK8_HEIGHT", constants.SoftFork8Height, "SOFT_FORK9_HEIGHT", constants.SoftFork9Height); err != nil {
+		errs = append(errs, err)
+	}
+
+	// The plot filter v2 adjustments only apply after hard fork 2, and must happen in order
+	adjustments := []struct {
+		name   string
+		height *uint32
+	}{
+		{"PLOT_FILTER_V2_FIRST_ADJUSTMENT_HEIGHT", constants.PlotFilterV2FirstAdjustmentHeight},
+		{"PLOT_FILTER_V2_SECOND_ADJUSTMENT_HEIGHT", constants.PlotFilterV2SecondAdjustmentHeight},
+		{"PLOT_FILTER_V2_THIRD_ADJUSTMENT_HEIGHT", constants.PlotFilterV2ThirdAdjustmentHeight},
+	}
+	for i, adjustment := range adjustments {
+		if adjustment.height == nil {
+			continue
+		}
+		if err := validateHeightOrder("HARD_FORK2_HEIGHT", constants.HardFork2Height, adjustment.name, adjustment.height); err != nil {
+			errs = append(errs, err)
+		}
+		if i > 0 {
+			if err := validateHeightOrder(adjustments[i-1].name, adjustments[i-1].height, adjustment.name, adjustment.height); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateGeneratedConstants checks a complete set of network constants, such as the output of network generate. These
+// set every value a network needs to produce blocks, rather than relying on chia's defaults, so none of them may be
+// zero.
+func validateGeneratedConstants(constants config.NetworkConstants) []error {
+	errs := validateNetworkConstants(constants)
+
+	if constants.MinPlotSize == 0 {
+		errs = append(errs, fmt.Errorf("MIN_PLOT_SIZE must be between %d and %d, got %d", minSaneMinPlotSize, maxSaneMinPlotSize, constants.MinPlotSize))
+	}
+	if constants.DifficultyStarting == 0 {
+		errs = append(errs, fmt.Errorf("DIFFICULTY_STARTING must not be zero"))
+	}
+	if constants.DifficultyConstantFactor.IsZero() {
+		errs = append(errs, fmt.Errorf("DIFFICULTY_CONSTANT_FACTOR must not be zero"))
+	}
+	if constants.SubSlotItersStarting == 0 {
+		errs = append(errs, fmt.Errorf("SUB_SLOT_ITERS_STARTING must not be zero"))
+	}
+	if constants.EpochBlocks == 0 {
+		errs = append(errs, fmt.Errorf("EPOCH_BLOCKS must not be zero"))
+	}
+
+	return errs
+}
+
+// validateBytes32 checks that value is a 32 byte hex string, with an optional 0x prefix
+func validateBytes32(value string) error {
+	if value == "" {
+		return fmt.Errorf("must be set")
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return fmt.Errorf("must be hex encoded: %w", err)
+	}
+	if len(decoded) != 32 {
+		return fmt.Errorf("must be 32 bytes, got %d", len(decoded))
+	}
+	return nil
+}
+
+// validateHeightOrder checks that the later height is not before the earlier height, when both are set
+func validateHeightOrder(earlierName string, earlier *uint32, laterName string, later *uint32) error {
+	if earlier == nil || later == nil {
+		return nil
+	}
+	if *later < *earlier {
+		return fmt.Errorf("%s (%d) must not be before %s (%d)", laterName, *later, earlierName, *earlier)
+	}
+	return nil
+}
+
+// logValidationErrors logs the problems found validating a network's constants. Returns true when there are none.
+func logValidationErrors(network string, errs []error) bool {
+	for _, err := range errs {
+		slogs.Logr.Error("invalid network constants", "network", network, "error", err)
+	}
+	return len(errs) == 0
+}
+
+func init() {
+	networkCmd.AddCommand(validateCmd)
+}
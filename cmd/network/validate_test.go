@@ -0,0 +1,118 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/ptr"
+	"github.com/chia-network/go-chia-libs/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPuzzleHash = "08296fc227decd043aee855741444538e4cc9a31772c4d1a9e6242d1e777e42a"
+
+func validTestConstants() config.NetworkConstants {
+	return config.NetworkConstants{
+		AggSigMeAdditionalData:             testPuzzleHash,
+		DifficultyConstantFactor:           types.Uint128From64(10052721566054),
+		DifficultyStarting:                 250,
+		EpochBlocks:                        768,
+		GenesisChallenge:                   testPuzzleHash,
+		GenesisPreFarmPoolPuzzleHash:       testPuzzleHash,
+		GenesisPreFarmFarmerPuzzleHash:     testPuzzleHash,
+		MinPlotSize:                        18,
+		SubSlotItersStarting:               1 << 25,
+		HardForkHeight:                     ptr.Uint32Ptr(0),
+		HardFork2Height:                    ptr.Uint32Ptr(100),
+		PlotFilterV2FirstAdjustmentHeight:  ptr.Uint32Ptr(200),
+		PlotFilterV2SecondAdjustmentHeight: ptr.Uint32Ptr(300),
+		PlotFilterV2ThirdAdjustmentHeight:  ptr.Uint32Ptr(400),
+	}
+}
+
+func TestValidateNetworkConstants(t *testing.T) {
+	assert.Empty(t, validateGeneratedConstants(validTestConstants()))
+
+	tests := map[string]struct {
+		modify   func(constants *config.NetworkConstants)
+		expected string
+	}{
+		"short puzzle hash": {
+			modify:   func(c *config.NetworkConstants) { c.GenesisPreFarmPoolPuzzleHash = "08296fc2" },
+			expected: "GENESIS_PRE_FARM_POOL_PUZZLE_HASH must be 32 bytes",
+		},
+		"non hex puzzle hash": {
+			modify:   func(c *config.NetworkConstants) { c.GenesisPreFarmFarmerPuzzleHash = "xch1notahash" },
+			expected: "GENESIS_PRE_FARM_FARMER_PUZZLE_HASH must be hex encoded",
+		},
+		"missing genesis challenge": {
+			modify:   func(c *config.NetworkConstants) { c.GenesisChallenge = "" },
+			expected: "GENESIS_CHALLENGE must be set",
+		},
+		"min plot size too small": {
+			modify:   func(c *config.NetworkConstants) { c.MinPlotSize = 10 },
+			expected: "MIN_PLOT_SIZE must be between",
+		},
+		"zero starting difficulty": {
+			modify:   func(c *config.NetworkConstants) { c.DifficultyStarting = 0 },
+			expected: "DIFFICULTY_STARTING must not be zero",
+		},
+		"zero difficulty constant factor": {
+			modify:   func(c *config.NetworkConstants) { c.DifficultyConstantFactor = types.Uint128From64(0) },
+			expected: "DIFFICULTY_CONSTANT_FACTOR must not be zero",
+		},
+		"zero sub slot iters": {
+			modify:   func(c *config.NetworkConstants) { c.SubSlotItersStarting = 0 },
+			expected: "SUB_SLOT_ITERS_STARTING must not be zero",
+		},
+		"hard forks out of order": {
+			modify:   func(c *config.NetworkConstants) { c.HardForkHeight = ptr.Uint32Ptr(150) },
+			expected: "HARD_FORK2_HEIGHT (100) must not be before HARD_FORK_HEIGHT (150)",
+		},
+		"soft forks out of order": {
+			modify: func(c *config.NetworkConstants) {
+				c.SoftFork8Height = ptr.Uint32Ptr(20)
+				c.SoftFork9Height = ptr.Uint32Ptr(10)
+			},
+			expected: "SOFT_FORK9_HEIGHT (10) must not be before SOFT_FORK8_HEIGHT (20)",
+		},
+		"adjustment before hard fork 2": {
+			modify:   func(c *config.NetworkConstants) { c.PlotFilterV2FirstAdjustmentHeight = ptr.Uint32Ptr(50) },
+			expected: "PLOT_FILTER_V2_FIRST_ADJUSTMENT_HEIGHT (50) must not be before HARD_FORK2_HEIGHT (100)",
+		},
+		"adjustments out of order": {
+			modify:   func(c *config.NetworkConstants) { c.PlotFilterV2ThirdAdjustmentHeight = ptr.Uint32Ptr(250) },
+			expected: "PLOT_FILTER_V2_THIRD_ADJUSTMENT_HEIGHT (250) must not be before PLOT_FILTER_V2_SECOND_ADJUSTMENT_HEIGHT (300)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			constants := validTestConstants()
+			test.modify(&constants)
+
+			var messages []string
+			for _, err := range validateGeneratedConstants(constants) {
+				messages = append(messages, err.Error())
+			}
+			assert.Contains(t, strings.Join(messages, "\n"), test.expected)
+		})
+	}
+}
+
+func TestValidateNetworkConstants_PartialOverrides(t *testing.T) {
+	// The mainnet constants in a stock config.yaml only set the genesis values, everything else uses chia's defaults
+	mainnet := config.NetworkConstants{
+		GenesisChallenge:               "ccd5bb71183532bff220ba46c268991a3ff07eb358e8255a65c30a2dce0e5fbb",
+		GenesisPreFarmPoolPuzzleHash:   "d23da14695a188ae5708dd152263c4db883eb27edeb936178d4d988b8f3ce5fc",
+		GenesisPreFarmFarmerPuzzleHash: "3d8765d3a597ec1d99663f6c9816d915b9f68613ac94009884c4addaefcce6af",
+	}
+	assert.Empty(t, validateNetworkConstants(mainnet))
+	// Generated constants don't rely on the defaults, so the same values are incomplete for them
+	assert.NotEmpty(t, validateGeneratedConstants(mainnet))
+
+	// Adjustment heights may be overridden without overriding the default hard fork 2 height
+	mainnet.PlotFilterV2FirstAdjustmentHeight = ptr.Uint32Ptr(200)
+	assert.Empty(t, validateNetworkConstants(mainnet))
+}
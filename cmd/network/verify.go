@@ -0,0 +1,106 @@
+package network
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// fetchURL downloads the body at rawURL. Plain http is refused unless insecure is set, since network definitions
+// are consensus critical.
+func fetchURL(rawURL string, insecure bool) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url: %w", err)
+	}
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !insecure {
+			return nil, fmt.Errorf("refusing to load %s over plain http. Use https, or pass --insecure", rawURL)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported url scheme %q", parsed.Scheme)
+	}
+
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", rawURL, err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status loading %s: %s", rawURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body of %s: %w", rawURL, err)
+	}
+
+	return body, nil
+}
+
+// verifySHA256 checks that the sha256 sum of data matches the expected hex digest
+func verifySHA256(data []byte, expected string) error {
+	expected = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(expected), "0x"))
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if actual != expected {
+		return fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// verifySignature checks that signature is a valid ed25519 signature of data by any of the public keys.
+// Keys and the signature may be hex or base64 encoded.
+func verifySignature(data []byte, signature []byte, publicKeys []string) error {
+	if len(publicKeys) == 0 {
+		return errors.New("no public keys are configured to verify the signature")
+	}
+
+	sig, err := decodeKeyMaterial(string(signature), ed25519.SignatureSize)
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %w", err)
+	}
+
+	for _, publicKey := range publicKeys {
+		key, err := decodeKeyMaterial(publicKey, ed25519.PublicKeySize)
+		if err != nil {
+			return fmt.Errorf("error decoding public key %s: %w", publicKey, err)
+		}
+		if ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	}
+
+	return errors.New("signature does not match any of the configured public keys")
+}
+
+// decodeKeyMaterial decodes a hex or base64 encoded value and checks it is the expected size
+func decodeKeyMaterial(encoded string, size int) ([]byte, error) {
+	encoded = strings.TrimPrefix(strings.TrimSpace(encoded), "0x")
+
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		decoded, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.New("value is neither hex nor base64 encoded")
+		}
+	}
+
+	if len(decoded) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(decoded))
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySHA256(t *testing.T) {
+	data := []byte("network_overrides: {}")
+	sum := sha256.Sum256(data)
+
+	assert.NoError(t, verifySHA256(data, hex.EncodeToString(sum[:])))
+	assert.NoError(t, verifySHA256(data, "0x"+hex.EncodeToString(sum[:])))
+	assert.Error(t, verifySHA256([]byte("tampered"), hex.EncodeToString(sum[:])))
+}
+
+func TestVerifySignature(t *testing.T) {
+	data := []byte("network_overrides: {}")
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signature := ed25519.Sign(privateKey, data)
+	hexSignature := []byte(hex.EncodeToString(signature) + "\n")
+	base64Signature := []byte(base64.StdEncoding.EncodeToString(signature))
+
+	assert.NoError(t, verifySignature(data, hexSignature, []string{hex.EncodeToString(publicKey)}))
+	assert.NoError(t, verifySignature(data, base64Signature, []string{base64.StdEncoding.EncodeToString(publicKey)}))
+	assert.NoError(t, verifySignature(data, hexSignature, []string{hex.EncodeToString(otherKey), hex.EncodeToString(publicKey)}))
+
+	assert.Error(t, verifySignature([]byte("tampered"), hexSignature, []string{hex.EncodeToString(publicKey)}))
+	assert.Error(t, verifySignature(data, hexSignature, []string{hex.EncodeToString(otherKey)}))
+	assert.Error(t, verifySignature(data, hexSignature, nil))
+	assert.Error(t, verifySignature(data, []byte("not a signature"), []string{hex.EncodeToString(publicKey)}))
+}
+
+func TestFetchURL_RefusesPlainHTTP(t *testing.T) {
+	_, err := fetchURL("http://example.com/config.yaml", false)
+	assert.ErrorContains(t, err, "plain http")
+
+	_, err = fetchURL("ftp://example.com/config.yaml", true)
+	assert.ErrorContains(t, err, "unsupported url scheme")
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/chia-network/go-modules/pkg/slogs"
+
+	"github.com/chia-network/chia-tools/internal/backups"
+	"github.com/chia-network/chia-tools/internal/lock"
 )
 
 var (
@@ -38,9 +41,13 @@ func init() {
 
 	RootCmd.PersistentFlags().String("log-level", "info", "The log-level for the application, can be one of info, warn, error, debug.")
 	RootCmd.PersistentFlags().Bool("dry-run", false, "Show what changes would be made without actually making them. For commands that modify data or configuration, this will show the old and new values.")
+	RootCmd.PersistentFlags().Int("config-backups", backups.DefaultRetention, "Number of config.yaml backups to keep in config/backups. Commands that modify the config back it up first. 0 disables backups")
+	RootCmd.PersistentFlags().Duration("lock-timeout", lock.DefaultTimeout, "How long commands that modify CHIA_ROOT wait for another chia-tools command to finish with it")
 
 	cobra.CheckErr(viper.BindPFlag("log-level", RootCmd.PersistentFlags().Lookup("log-level")))
 	cobra.CheckErr(viper.BindPFlag("dry-run", RootCmd.PersistentFlags().Lookup("dry-run")))
+	cobra.CheckErr(viper.BindPFlag("config-backups", RootCmd.PersistentFlags().Lookup("config-backups")))
+	cobra.CheckErr(viper.BindPFlag("lock-timeout", RootCmd.PersistentFlags().Lookup("lock-timeout")))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,253 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+)
+
+// DirName is the directory next to config.yaml that backups are stored in
+const DirName = "backups"
+
+// DefaultRetention is the default number of backups to keep
+const DefaultRetention = 20
+
+// timestampFormat sorts in the same order as the times, and includes milliseconds so quick successive saves do not
+// overwrite each other
+const timestampFormat = "20060102T150405.000Z"
+
+// Backup is a copy of config.yaml, stored as config.yaml.<timestamp>.<command>
+type Backup struct {
+	// ID identifies the backup for restore, and is the part of the file name after config.yaml.
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Command string    `json:"command"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+}
+
+// Dir returns the directory backups of configPath are stored in
+func Dir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DirName)
+}
+
+// SaveConfig backs up configPath and then saves cfg to it. command is recorded in the name of the backup. The number
+// of backups kept is set with --config-backups.
+func SaveConfig(cfg *config.ChiaConfig, configPath, command string) error {
+	_, err := Create(configPath, command, viper.GetInt("config-backups"))
+	if err != nil {
+		return err
+	}
+	return cfg.SavePath(configPath)
+}
+
+// Create copies configPath into the backups directory, then removes the oldest backups so only retain are kept.
+// Returns nil without a backup when configPath does not exist or retain is 0.
+func Create(configPath, command string, retain int) (*Backup, error) {
+	if retain <= 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading config for backup: %w", err)
+	}
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config for backup: %w", err)
+	}
+
+	backupDir := Dir(configPath)
+	err = os.MkdirAll(backupDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating backup directory: %w", err)
+	}
+
+	created := time.Now().UTC()
+	command = sanitizeCommand(command)
+	id := fmt.Sprintf("%s.%s", created.Format(timestampFormat), command)
+	backup := &Backup{
+		ID:      id,
+		Path:    filepath.Join(backupDir, filepath.Base(configPath)+"."+id),
+		Command: command,
+		Created: created,
+		Size:    int64(len(data)),
+	}
+	err = os.WriteFile(backup.Path, data, info.Mode().Perm())
+	if err != nil {
+		return nil, fmt.Errorf("error writing config backup: %w", err)
+	}
+	slogs.Logr.Info("Backed up config", "path", backup.Path)
+
+	err = prune(configPath, retain)
+	if err != nil {
+		return backup, err
+	}
+
+	return backup, nil
+}
+
+// List returns the backups of configPath, newest first
+func List(configPath string) ([]Backup, error) {
+	backupDir := Dir(configPath)
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading backup directory: %w", err)
+	}
+
+	prefix := filepath.Base(configPath) + "."
+	var backups []Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		id := strings.TrimPrefix(entry.Name(), prefix)
+		created, command, ok := parseID(id)
+		if !ok {
+			slogs.Logr.Debug("skipping file in backup directory that is not a backup", "file", entry.Name())
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("error reading backup: %w", err)
+		}
+		backups = append(backups, Backup{
+			ID:      id,
+			Path:    filepath.Join(backupDir, entry.Name()),
+			Command: command,
+			Created: created,
+			Size:    info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups, nil
+}
+
+// Find returns the backup of configPath with the id. A unique prefix of the id, such as just the timestamp, also
+// matches.
+func Find(configPath, id string) (*Backup, error) {
+	backups, err := List(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Backup
+	for _, backup := range backups {
+		if backup.ID == id {
+			return &backup, nil
+		}
+		if strings.HasPrefix(backup.ID, id) {
+			matches = append(matches, backup)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no backup found with id %s", id)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("%d backups match %s. Use the full id", len(matches), id)
+}
+
+// Restore replaces configPath with the backup. The current config is backed up first, so the restore can be undone.
+func Restore(configPath string, backup *Backup, retain int) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("error reading backup: %w", err)
+	}
+
+	_, err = Create(configPath, "config-backups-restore", retain)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	// Write next to the config and rename it into place, so an interrupted restore never leaves a partial config
+	tmpFile, err := os.CreateTemp(filepath.Dir(configPath), filepath.Base(configPath)+".restore-*")
+	if err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, mode)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, configPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("error writing config: %w", err)
+	}
+
+	return nil
+}
+
+// prune removes the oldest backups of configPath so only retain are kept
+func prune(configPath string, retain int) error {
+	backups, err := List(configPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= retain {
+		return nil
+	}
+	for _, backup := range backups[retain:] {
+		slogs.Logr.Debug("removing old config backup", "path", backup.Path)
+		err = os.Remove(backup.Path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error removing old config backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseID splits a backup id into the time the backup was created and the command that created it
+func parseID(id string) (time.Time, string, bool) {
+	if len(id) <= len(timestampFormat)+1 || id[len(timestampFormat)] != '.' {
+		return time.Time{}, "", false
+	}
+	created, err := time.Parse(timestampFormat, id[:len(timestampFormat)])
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return created, id[len(timestampFormat)+1:], true
+}
+
+// sanitizeCommand makes a command name safe to use in a file name, such as "config edit" to "config-edit"
+func sanitizeCommand(command string) string {
+	command = strings.Join(strings.Fields(command), "-")
+	command = strings.Map(func(r rune) rune {
+		if r == filepath.Separator || r == '/' || r == '\\' || r == ':' {
+			return '-'
+		}
+		return r
+	}, command)
+	if command == "" {
+		return "unknown"
+	}
+	return command
+}
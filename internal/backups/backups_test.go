@@ -0,0 +1,112 @@
+package backups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, configPath, contents string) {
+	assert.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+}
+
+func TestCreate_Retention(t *testing.T) {
+	slogs.Init("info")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	// Nothing to back up yet
+	backup, err := Create(configPath, "config edit", 2)
+	assert.NoError(t, err)
+	assert.Nil(t, backup)
+
+	for _, contents := range []string{"one", "two", "three"} {
+		writeConfig(t, configPath, contents)
+		backup, err = Create(configPath, "config edit", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "config-edit", backup.Command)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Only the newest two are kept, newest first
+	configBackups, err := List(configPath)
+	assert.NoError(t, err)
+	assert.Len(t, configBackups, 2)
+	assert.Equal(t, backup.ID, configBackups[0].ID)
+	for i, contents := range []string{"three", "two"} {
+		data, err := os.ReadFile(configBackups[i].Path)
+		assert.NoError(t, err)
+		assert.Equal(t, contents, string(data))
+		assert.Equal(t, filepath.Join(Dir(configPath), "config.yaml."+configBackups[i].ID), configBackups[i].Path)
+	}
+
+	// Backups can be turned off
+	backup, err = Create(configPath, "config edit", 0)
+	assert.NoError(t, err)
+	assert.Nil(t, backup)
+}
+
+func TestFindAndRestore(t *testing.T) {
+	slogs.Init("info")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	writeConfig(t, configPath, "good")
+	good, err := Create(configPath, "config edit", DefaultRetention)
+	assert.NoError(t, err)
+	writeConfig(t, configPath, "bad")
+
+	// Other files in the directory are ignored
+	assert.NoError(t, os.WriteFile(filepath.Join(Dir(configPath), "notes.txt"), []byte("notes"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(Dir(configPath), "config.yaml.not-a-backup"), []byte("notes"), 0644))
+
+	found, err := Find(configPath, good.ID[:15])
+	assert.NoError(t, err)
+	assert.Equal(t, good.ID, found.ID)
+	_, err = Find(configPath, "19990101")
+	assert.Error(t, err)
+
+	assert.NoError(t, Restore(configPath, found, DefaultRetention))
+	data, err := os.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "good", string(data))
+
+	// The config that was replaced is backed up, so the restore can be undone
+	configBackups, err := List(configPath)
+	assert.NoError(t, err)
+	assert.Len(t, configBackups, 2)
+	assert.Equal(t, "config-backups-restore", configBackups[0].Command)
+	data, err = os.ReadFile(configBackups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "bad", string(data))
+
+	// The restored config is renamed into place, so no temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(configPath))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestSaveConfig(t *testing.T) {
+	slogs.Init("info")
+	viper.Set("config-backups", DefaultRetention)
+	defer viper.Set("config-backups", nil)
+
+	// A config other than the one in CHIA_ROOT, such as from config edit --config
+	chiaRoot := t.TempDir()
+	configPath := filepath.Join(t.TempDir(), "other.yaml")
+	writeConfig(t, configPath, "original")
+
+	assert.NoError(t, SaveConfig(&config.ChiaConfig{ChiaRoot: chiaRoot}, configPath, "config edit"))
+
+	configBackups, err := List(configPath)
+	assert.NoError(t, err)
+	assert.Len(t, configBackups, 1)
+	data, err := os.ReadFile(configBackups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "original", string(data))
+	assert.NoFileExists(t, filepath.Join(chiaRoot, "config", "config.yaml"))
+}
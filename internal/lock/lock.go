@@ -0,0 +1,167 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// FileName is the name of the lock file in CHIA_ROOT
+const FileName = "chia-tools.lock"
+
+// DefaultTimeout is the default time to wait for another command to release the lock
+const DefaultTimeout = 30 * time.Second
+
+// pollInterval is how often the lock is checked while waiting for it
+const pollInterval = 250 * time.Millisecond
+
+// ErrTimeout is returned when the lock is still held by another process at the timeout
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// Lock is an advisory lock on a CHIA_ROOT, held by a single chia-tools command
+type Lock struct {
+	path string
+}
+
+// owner is the content of the lock file, describing the process that holds the lock
+type owner struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Command  string    `json:"command"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// Acquire takes the lock on chiaRoot for command, waiting up to timeout for another process to release it.
+// A lock left behind by a process on this host that is no longer running is removed.
+func Acquire(chiaRoot, command string, timeout time.Duration) (*Lock, error) {
+	lockPath := filepath.Join(chiaRoot, FileName)
+	hostname, _ := os.Hostname()
+	marshalled, err := json.Marshal(owner{
+		PID:      os.Getpid(),
+		Hostname: hostname,
+		Command:  command,
+		Acquired: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling lock: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = file.Write(marshalled)
+			closeErr := file.Close()
+			if err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("error writing lock file: %w", err)
+			}
+			slogs.Logr.Debug("acquired lock", "path", lockPath)
+			return &Lock{path: lockPath}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("error creating lock file: %w", err)
+		}
+
+		current, err := readOwner(lockPath)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			// Released between the create and the read
+			continue
+		}
+		if current.PID != 0 && current.Hostname == hostname && !processExists(current.PID) {
+			err = removeStale(lockPath, current)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			if current.PID == 0 {
+				return nil, fmt.Errorf("%w on %s, held by an unknown command. If no chia-tools command is running, %s can be removed", ErrTimeout, chiaRoot, lockPath)
+			}
+			return nil, fmt.Errorf("%w on %s, held by %s (pid %d on %s) since %s", ErrTimeout, chiaRoot, current.Command, current.PID, current.Hostname, current.Acquired.Format(time.RFC3339))
+		}
+		if !waiting {
+			slogs.Logr.Info("waiting for another chia-tools command to finish", "command", current.Command, "pid", current.PID, "timeout", timeout)
+			waiting = true
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release removes the lock
+func (l *Lock) Release() error {
+	err := os.Remove(l.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing lock file: %w", err)
+	}
+	slogs.Logr.Debug("released lock", "path", l.path)
+	return nil
+}
+
+// removeStale removes the lock file left behind by stale. The lock file is first renamed to a name only this process
+// uses, so that when several processes find the same stale lock, only one of them removes it. If another process
+// already replaced the stale lock with its own, that lock is put back instead.
+func removeStale(lockPath string, stale *owner) error {
+	takeoverPath := fmt.Sprintf("%s.stale-%d-%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	err := os.Rename(lockPath, takeoverPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error removing stale lock file: %w", err)
+	}
+
+	renamed, err := readOwner(takeoverPath)
+	if err != nil {
+		return err
+	}
+	if renamed == nil || *renamed != *stale {
+		slogs.Logr.Debug("lock was taken by another process while removing a stale lock, putting it back", "path", lockPath)
+		err = os.Rename(takeoverPath, lockPath)
+		if err != nil {
+			return fmt.Errorf("error restoring lock file: %w", err)
+		}
+		return nil
+	}
+
+	slogs.Logr.Warn("removing stale lock left by a process that is no longer running", "pid", stale.PID, "command", stale.Command)
+	err = os.Remove(takeoverPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing stale lock file: %w", err)
+	}
+	return nil
+}
+
+// readOwner returns the owner of the lock, or nil if the lock file no longer exists. A lock file that is empty or
+// can't be parsed may have just been created by another process that hasn't written it yet, so it is returned as held
+// by an unknown owner, with a zero PID.
+func readOwner(lockPath string) (*owner, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading lock file: %w", err)
+	}
+	current := &owner{}
+	err = json.Unmarshal(data, current)
+	if err != nil {
+		slogs.Logr.Debug("lock file is empty or incomplete, treating it as held", "path", lockPath, "error", err)
+		return &owner{}, nil
+	}
+	return current, nil
+}
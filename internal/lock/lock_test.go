@@ -0,0 +1,108 @@
+package lock
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	slogs.Init("info")
+	chiaRoot := t.TempDir()
+
+	held, err := Acquire(chiaRoot, "network switch", time.Second)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(chiaRoot, FileName))
+
+	// The lock is held by a running process, so a second command times out
+	_, err = Acquire(chiaRoot, "config edit", 300*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorContains(t, err, "network switch")
+
+	// A waiting command gets the lock once it is released
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		assert.NoError(t, held.Release())
+	}()
+	waited, err := Acquire(chiaRoot, "config edit", 5*time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, waited.Release())
+	assert.NoFileExists(t, filepath.Join(chiaRoot, FileName))
+}
+
+func TestAcquire_Stale(t *testing.T) {
+	slogs.Init("info")
+	chiaRoot := t.TempDir()
+
+	// A lock left behind by a process that has exited
+	hostname, _ := os.Hostname()
+	stale, err := json.Marshal(owner{PID: 999999999, Hostname: hostname, Command: "network switch", Acquired: time.Now()})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(chiaRoot, FileName), stale, 0644))
+
+	acquired, err := Acquire(chiaRoot, "config edit", 0)
+	assert.NoError(t, err)
+	assert.NoError(t, acquired.Release())
+
+	// The same lock from another host can't be checked, so it is not removed
+	stale, err = json.Marshal(owner{PID: 999999999, Hostname: hostname + "-other", Command: "network switch", Acquired: time.Now()})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(chiaRoot, FileName), stale, 0644))
+
+	_, err = Acquire(chiaRoot, "config edit", 0)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestAcquire_Incomplete(t *testing.T) {
+	slogs.Init("info")
+	chiaRoot := t.TempDir()
+	lockPath := filepath.Join(chiaRoot, FileName)
+
+	// A lock file that was created, but not written yet, is still held
+	assert.NoError(t, os.WriteFile(lockPath, nil, 0644))
+	_, err := Acquire(chiaRoot, "config edit", 300*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.FileExists(t, lockPath)
+
+	// The waiting command gets the lock once the other command finishes writing and releases it
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		assert.NoError(t, os.Remove(lockPath))
+	}()
+	acquired, err := Acquire(chiaRoot, "config edit", 5*time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, acquired.Release())
+}
+
+func TestRemoveStale(t *testing.T) {
+	slogs.Init("info")
+	chiaRoot := t.TempDir()
+	lockPath := filepath.Join(chiaRoot, FileName)
+	hostname, _ := os.Hostname()
+
+	stale := owner{PID: 999999999, Hostname: hostname, Command: "network switch", Acquired: time.Now().UTC().Truncate(time.Second)}
+	live := owner{PID: os.Getpid(), Hostname: hostname, Command: "config edit", Acquired: time.Now().UTC().Truncate(time.Second)}
+
+	// Another process already replaced the stale lock with its own, so it is left in place
+	marshalled, err := json.Marshal(live)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(lockPath, marshalled, 0644))
+	assert.NoError(t, removeStale(lockPath, &stale))
+	current, err := readOwner(lockPath)
+	assert.NoError(t, err)
+	assert.Equal(t, live, *current)
+
+	// The stale lock itself is removed, without leaving the renamed file behind
+	marshalled, err = json.Marshal(stale)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(lockPath, marshalled, 0644))
+	assert.NoError(t, removeStale(lockPath, &stale))
+	entries, err := os.ReadDir(chiaRoot)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package lock
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code windows reports for a process that has not exited
+const stillActive = 259
+
+// processExists checks if a process with the pid is running
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access is denied for processes owned by other users, which means the process exists
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer func() {
+		_ = windows.CloseHandle(handle)
+	}()
+
+	var exitCode uint32
+	err = windows.GetExitCodeProcess(handle, &exitCode)
+	if err != nil {
+		return true
+	}
+	return exitCode == stillActive
+}
@@ -0,0 +1,294 @@
+package probe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Inventory is a list of nodes to probe together. It is loaded from a YAML or JSON file such as:
+//
+//	nodes:
+//	  - name: farmer-1
+//	    host: 10.0.0.5
+//	    cert_dir: /etc/chia-tools/farmer-1/ssl
+//	    services:
+//	      harvester:
+//	        host: 10.0.0.6
+//	        port: 8560
+type Inventory struct {
+	Nodes []InventoryNode `yaml:"nodes" json:"nodes"`
+}
+
+// InventoryNode is a single node in the inventory. Services overrides the host or port of individual services,
+// keyed by the same service names as the --<service>-host/port flags.
+type InventoryNode struct {
+	Name     string                       `yaml:"name" json:"name"`
+	Host     string                       `yaml:"host" json:"host"`
+	CertDir  string                       `yaml:"cert_dir" json:"cert_dir"`
+	Services map[string]InventoryEndpoint `yaml:"services" json:"services"`
+}
+
+// InventoryEndpoint overrides where a single service on a node is reached
+type InventoryEndpoint struct {
+	Host string `yaml:"host" json:"host"`
+	Port uint16 `yaml:"port" json:"port"`
+}
+
+// NodeResults are the results of probing every service on a single node
+type NodeResults struct {
+	Node string
+	// Network is the network selected in the node's config, if it is known. It is empty for a remote node without a
+	// copy of its config.yaml.
+	Network string
+	Results []Result
+	// Err is set when the node could not be probed at all
+	Err error
+}
+
+// FleetRow is a single node and service in the JSON output for a fleet
+type FleetRow struct {
+	Node          string `json:"node"`
+	Service       string `json:"service"`
+	ConfigNetwork string `json:"config_network,omitempty"`
+	Running       bool   `json:"running"`
+	Version       string `json:"version,omitempty"`
+	Network       string `json:"network,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// LoadInventory reads a YAML or JSON inventory file
+func LoadInventory(file string) (*Inventory, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading inventory: %w", err)
+	}
+
+	// JSON is valid YAML, so both are decoded the same way
+	inventory := &Inventory{}
+	err = yaml.Unmarshal(data, inventory)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling inventory: %w", err)
+	}
+	if len(inventory.Nodes) == 0 {
+		return nil, errors.New("inventory does not contain any nodes")
+	}
+
+	names := map[string]bool{}
+	for i, node := range inventory.Nodes {
+		if node.Name == "" {
+			return nil, fmt.Errorf("inventory node %d does not have a name", i)
+		}
+		if names[node.Name] {
+			return nil, fmt.Errorf("inventory node %s is listed more than once", node.Name)
+		}
+		names[node.Name] = true
+		for service := range node.Services {
+			if !isServiceKey(service) {
+				return nil, fmt.Errorf("inventory node %s has unknown service %s. Expected one of %s", node.Name, service, strings.Join(serviceKeys, ", "))
+			}
+		}
+	}
+
+	return inventory, nil
+}
+
+// Target returns the target for the node
+func (n InventoryNode) Target() Target {
+	target := Target{
+		Host:      n.Host,
+		CertDir:   n.CertDir,
+		Endpoints: map[string]Endpoint{},
+	}
+	for service, endpoint := range n.Services {
+		target.Endpoints[service] = Endpoint(endpoint)
+	}
+	return target
+}
+
+// FleetFromFlags probes the nodes in the inventory from the flags added with AddFlags. Without an inventory, the single
+// target from the flags is probed instead.
+func FleetFromFlags(prefix string, timeout time.Duration) ([]NodeResults, error) {
+	inventoryFile := viper.GetString(prefix + "-inventory")
+	if inventoryFile == "" {
+		target := TargetFromFlags(prefix)
+		name := target.Host
+		if name == "" {
+			name = "local"
+		}
+		return []NodeResults{ProbeTarget(name, target, timeout)}, nil
+	}
+
+	inventory, err := LoadInventory(inventoryFile)
+	if err != nil {
+		return nil, err
+	}
+	return Fleet(inventory, timeout), nil
+}
+
+// Fleet queries the version and network of every service on every node in parallel.
+// Results are in the same order as the nodes in the inventory.
+func Fleet(inventory *Inventory, timeout time.Duration) []NodeResults {
+	fleet := make([]NodeResults, len(inventory.Nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range inventory.Nodes {
+		wg.Add(1)
+		go func(i int, node InventoryNode) {
+			defer wg.Done()
+			fleet[i] = ProbeTarget(node.Name, node.Target(), timeout)
+		}(i, node)
+	}
+	wg.Wait()
+
+	return fleet
+}
+
+// ProbeTarget queries the version and network of every service on a single target
+func ProbeTarget(name string, target Target, timeout time.Duration) NodeResults {
+	nodeResults := NodeResults{Node: name}
+
+	cfg, err := target.Config()
+	if err != nil {
+		nodeResults.Err = err
+		return nodeResults
+	}
+	// The local config's network is not the network of a remote node, so it is left unknown
+	if cfg.SelectedNetwork != nil && target.HasNodeConfig() {
+		nodeResults.Network = *cfg.SelectedNetwork
+	}
+
+	services, err := Services(target, timeout)
+	if err != nil {
+		nodeResults.Err = err
+		return nodeResults
+	}
+
+	nodeResults.Results = versionsAndNetworks(services, timeout)
+
+	return nodeResults
+}
+
+// versionsAndNetworks queries the version and then the network of every service. Each service's client, including the
+// daemon's websocket client, is only used for one request at a time, so the network is only queried once the version
+// query finished. A service whose version query timed out may still be using its client, so it is not queried again.
+func versionsAndNetworks(services []Service, timeout time.Duration) []Result {
+	results := Versions(services, timeout)
+
+	var responsive []Service
+	var responsiveIndexes []int
+	for i, version := range results {
+		if !errors.Is(version.Err, ErrTimeout) {
+			responsive = append(responsive, services[i])
+			responsiveIndexes = append(responsiveIndexes, i)
+		}
+	}
+	networks := Networks(responsive, timeout)
+
+	for j, i := range responsiveIndexes {
+		result := &results[i]
+		result.Network = networks[j].Network
+		if !result.Running && networks[j].Running {
+			result.Running = true
+			result.Err = nil
+		}
+	}
+
+	return results
+}
+
+// WriteFleetTable writes a table with a row for each node and a column for each service. value formats the cell for
+// a single service.
+func WriteFleetTable(w io.Writer, fleet []NodeResults, value func(node NodeResults, result Result) string) {
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+
+	var labels []string
+	for _, node := range fleet {
+		if len(node.Results) > 0 {
+			for _, result := range node.Results {
+				labels = append(labels, result.Label)
+			}
+			break
+		}
+	}
+	_, _ = fmt.Fprintf(tw, "Node\tConfig\t%s\n", strings.Join(labels, "\t"))
+
+	for _, node := range fleet {
+		if node.Err != nil {
+			_, _ = fmt.Fprintf(tw, "%s\t-\tError: %s\n", node.Node, node.Err)
+			continue
+		}
+		configNetwork := node.Network
+		if configNetwork == "" {
+			configNetwork = "-"
+		}
+		cells := make([]string, len(node.Results))
+		for i, result := range node.Results {
+			cells[i] = value(node, result)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", node.Node, configNetwork, strings.Join(cells, "\t"))
+	}
+
+	_ = tw.Flush()
+}
+
+// WriteFleetJSON writes a row for every node and service as JSON
+func WriteFleetJSON(w io.Writer, fleet []NodeResults) error {
+	rows := []FleetRow{}
+	for _, node := range fleet {
+		if node.Err != nil {
+			rows = append(rows, FleetRow{Node: node.Node, Error: node.Err.Error()})
+			continue
+		}
+		for _, result := range node.Results {
+			row := FleetRow{
+				Node:          node.Node,
+				Service:       result.Label,
+				ConfigNetwork: node.Network,
+				Running:       result.Running,
+				Version:       result.Version,
+				Network:       result.Network,
+			}
+			if result.Err != nil {
+				row.Error = result.Err.Error()
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	marshalled, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling results: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(marshalled))
+	return err
+}
+
+// Status describes whether a service is running, not running, or did not respond in time
+func Status(result Result) string {
+	switch {
+	case errors.Is(result.Err, ErrTimeout):
+		return "Not Responding"
+	case !result.Running:
+		return "Not Running"
+	}
+	return "Running"
+}
+
+func isServiceKey(service string) bool {
+	for _, key := range serviceKeys {
+		if key == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeInventory(t *testing.T, contents string) string {
+	file := filepath.Join(t.TempDir(), "inventory.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(contents), 0644))
+	return file
+}
+
+func TestLoadInventory(t *testing.T) {
+	file := writeInventory(t, `nodes:
+  - name: farmer-1
+    host: 10.0.0.5
+    cert_dir: /tmp/farmer-1/config/ssl
+    services:
+      harvester:
+        host: 10.0.0.6
+        port: 18560
+  - name: node-2
+    host: 10.0.0.7
+`)
+
+	inventory, err := LoadInventory(file)
+	assert.NoError(t, err)
+	assert.Len(t, inventory.Nodes, 2)
+
+	target := inventory.Nodes[0].Target()
+	assert.Equal(t, "10.0.0.5", target.Host)
+	assert.Equal(t, "/tmp/farmer-1/config/ssl", target.CertDir)
+	assert.Equal(t, map[string]Endpoint{"harvester": {Host: "10.0.0.6", Port: 18560}}, target.Endpoints)
+
+	assert.False(t, inventory.Nodes[1].Target().IsLocal())
+}
+
+func TestLoadInventory_JSON(t *testing.T) {
+	file := writeInventory(t, `{"nodes": [{"name": "node-1", "host": "10.0.0.5", "services": {"full-node": {"port": 18555}}}]}`)
+
+	inventory, err := LoadInventory(file)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Endpoint{"full-node": {Port: 18555}}, inventory.Nodes[0].Target().Endpoints)
+}
+
+func TestLoadInventory_Invalid(t *testing.T) {
+	for name, contents := range map[string]string{
+		"empty":           "nodes: []\n",
+		"missing name":    "nodes:\n  - host: 10.0.0.5\n",
+		"duplicate name":  "nodes:\n  - name: node-1\n  - name: node-1\n",
+		"unknown service": "nodes:\n  - name: node-1\n    services:\n      fullnode:\n        port: 8555\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadInventory(writeInventory(t, contents))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func testFleet() []NodeResults {
+	return []NodeResults{
+		{
+			Node:    "node-1",
+			Network: "mainnet",
+			Results: []Result{
+				{Label: "Daemon", Running: true, Version: "2.5.0", Network: "mainnet"},
+				{Label: "Full Node", Err: ErrTimeout},
+			},
+		},
+		{
+			Node: "node-2",
+			Err:  errors.New("error loading config"),
+		},
+	}
+}
+
+func TestWriteFleetTable(t *testing.T) {
+	var out bytes.Buffer
+	WriteFleetTable(&out, testFleet(), func(node NodeResults, result Result) string {
+		if !result.Running {
+			return Status(result)
+		}
+		return result.Version
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"Node", "Config", "Daemon", "Full", "Node"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"node-1", "mainnet", "2.5.0", "Not", "Responding"}, strings.Fields(lines[1]))
+	assert.Contains(t, lines[2], "Error: error loading config")
+}
+
+func TestWriteFleetJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteFleetJSON(&out, testFleet()))
+
+	var rows []FleetRow
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+	assert.Equal(t, []FleetRow{
+		{Node: "node-1", Service: "Daemon", ConfigNetwork: "mainnet", Running: true, Version: "2.5.0", Network: "mainnet"},
+		{Node: "node-1", Service: "Full Node", ConfigNetwork: "mainnet", Error: ErrTimeout.Error()},
+		{Node: "node-2", Error: "error loading config"},
+	}, rows)
+}
+
+func TestVersionsAndNetworks(t *testing.T) {
+	slogs.Init("info")
+
+	running := &fakeService{delay: 100 * time.Millisecond, version: "2.5.0"}
+	stopped := &fakeService{delay: 100 * time.Millisecond, err: errors.New("connection refused")}
+	hung := &fakeService{delay: 2 * time.Second}
+	services := []Service{
+		{Label: "Running", Client: running},
+		{Label: "Stopped", Client: stopped},
+		{Label: "Hung", Client: hung},
+	}
+
+	results := versionsAndNetworks(services, 500*time.Millisecond)
+	assert.Equal(t, Result{Label: "Running", Running: true, Version: "2.5.0"}, results[0])
+	assert.False(t, results[1].Running)
+	assert.ErrorIs(t, results[2].Err, ErrTimeout)
+
+	// Each client only handles one request at a time, and the hung service isn't queried again
+	assert.Equal(t, int32(1), running.maxInFlight.Load())
+	assert.Equal(t, int32(1), stopped.maxInFlight.Load())
+	assert.Equal(t, int32(1), running.networkCalls.Load())
+	assert.Equal(t, int32(0), hung.networkCalls.Load())
+}
@@ -0,0 +1,139 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// DefaultTimeout is the default time to wait for each service to respond
+const DefaultTimeout = 5 * time.Second
+
+// ErrTimeout is returned for a service that did not respond within the timeout
+var ErrTimeout = errors.New("timed out waiting for service")
+
+// Prober is the subset of RPC calls every chia service supports
+type Prober interface {
+	GetVersion(opts *rpc.GetVersionOptions) (*rpc.GetVersionResponse, *http.Response, error)
+	GetNetworkInfo(opts *rpc.GetNetworkInfoOptions) (*rpc.GetNetworkInfoResponse, *http.Response, error)
+}
+
+// Service is a chia service that can be probed
+type Service struct {
+	Label  string
+	Client Prober
+}
+
+// Result is the outcome of probing a single service
+type Result struct {
+	Label string
+	// Running is true when the service responded to the probe
+	Running bool
+	// Version is the version the service reported, if the probe asked for it
+	Version string
+	// Network is the network the service reported, if the probe asked for it
+	Network string
+	// Err is the error from the service, or ErrTimeout if it did not respond in time
+	Err error
+}
+
+// Services returns every chia service on the target. Every RPC call made through the services is limited to timeout.
+func Services(target Target, timeout time.Duration) ([]Service, error) {
+	configOptions, err := target.configOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Each service gets its own client, so services can be reached on different hosts
+	clients := make([]*rpc.Client, len(serviceKeys))
+	for i, service := range serviceKeys {
+		mode := rpc.ConnectionModeHTTP
+		if service == "daemon" {
+			mode = rpc.ConnectionModeWebsocket
+		}
+		slogs.Logr.Debug("initializing client", "service", service)
+		clients[i], err = rpc.NewClient(mode, configOptions[i], rpc.WithSyncWebsocket(), rpc.WithTimeout(timeout))
+		if err != nil {
+			return nil, fmt.Errorf("error initializing client for %s: %w", service, err)
+		}
+	}
+
+	return []Service{
+		{Label: "Daemon", Client: clients[0].DaemonService},
+		{Label: "Full Node", Client: clients[1].FullNodeService},
+		{Label: "Wallet", Client: clients[2].WalletService},
+		{Label: "Farmer", Client: clients[3].FarmerService},
+		{Label: "Harvester", Client: clients[4].HarvesterService},
+		{Label: "Crawler", Client: clients[5].CrawlerService},
+		{Label: "Data Layer", Client: clients[6].DataLayerService},
+		{Label: "Timelord", Client: clients[7].TimelordService},
+	}, nil
+}
+
+// Versions queries the version of every service in parallel. Results are in the same order as services.
+func Versions(services []Service, timeout time.Duration) []Result {
+	return probeAll(services, timeout, func(service Service) Result {
+		version, _, err := service.Client.GetVersion(&rpc.GetVersionOptions{})
+		if err != nil {
+			return Result{Err: err}
+		}
+		if version == nil {
+			return Result{Running: true}
+		}
+		return Result{Running: true, Version: version.Version}
+	})
+}
+
+// Networks queries the network of every service in parallel. Results are in the same order as services.
+func Networks(services []Service, timeout time.Duration) []Result {
+	return probeAll(services, timeout, func(service Service) Result {
+		network, _, err := service.Client.GetNetworkInfo(&rpc.GetNetworkInfoOptions{})
+		if err != nil {
+			return Result{Err: err}
+		}
+		if network == nil || network.NetworkName.IsAbsent() {
+			return Result{Running: true}
+		}
+		return Result{Running: true, Network: network.NetworkName.MustGet()}
+	})
+}
+
+// probeAll runs call against every service at once, and waits up to timeout for each one.
+// A service that does not respond in time is reported with ErrTimeout, and its call is abandoned.
+func probeAll(services []Service, timeout time.Duration, call func(Service) Result) []Result {
+	results := make([]Result, len(services))
+
+	var wg sync.WaitGroup
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service Service) {
+			defer wg.Done()
+
+			// Buffered, so the call can finish after a timeout without blocking forever
+			done := make(chan Result, 1)
+			go func() {
+				done <- call(service)
+			}()
+
+			var result Result
+			select {
+			case result = <-done:
+			case <-time.After(timeout):
+				result = Result{Err: ErrTimeout}
+			}
+			result.Label = service.Label
+			if result.Err != nil {
+				slogs.Logr.Debug("error probing service", "service", service.Label, "error", result.Err)
+			}
+			results[i] = result
+		}(i, service)
+	}
+	wg.Wait()
+
+	return results
+}
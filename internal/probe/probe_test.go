@@ -0,0 +1,94 @@
+package probe
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	delay   time.Duration
+	version string
+	err     error
+
+	// inFlight and maxInFlight track how many calls the service handled at once, and networkCalls how many network
+	// queries it received
+	inFlight     atomic.Int32
+	maxInFlight  atomic.Int32
+	networkCalls atomic.Int32
+}
+
+// call records the call as in flight for the delay
+func (f *fakeService) call() {
+	current := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+	for {
+		highest := f.maxInFlight.Load()
+		if current <= highest || f.maxInFlight.CompareAndSwap(highest, current) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+}
+
+func (f *fakeService) GetVersion(opts *rpc.GetVersionOptions) (*rpc.GetVersionResponse, *http.Response, error) {
+	f.call()
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &rpc.GetVersionResponse{Version: f.version}, nil, nil
+}
+
+func (f *fakeService) GetNetworkInfo(opts *rpc.GetNetworkInfoOptions) (*rpc.GetNetworkInfoResponse, *http.Response, error) {
+	f.networkCalls.Add(1)
+	f.call()
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return nil, nil, nil
+}
+
+func TestVersions(t *testing.T) {
+	slogs.Init("info")
+
+	services := []Service{
+		{Label: "Running", Client: &fakeService{delay: 200 * time.Millisecond, version: "2.5.0"}},
+		{Label: "Stopped", Client: &fakeService{delay: 200 * time.Millisecond, err: errors.New("connection refused")}},
+		{Label: "Hung", Client: &fakeService{delay: 5 * time.Second}},
+	}
+
+	start := time.Now()
+	results := Versions(services, time.Second)
+	elapsed := time.Since(start)
+
+	// Services are queried in parallel, and the hung service is abandoned at the timeout
+	assert.True(t, elapsed < 2*time.Second, "probing took %s", elapsed)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, Result{Label: "Running", Running: true, Version: "2.5.0"}, results[0])
+	assert.Equal(t, "Stopped", results[1].Label)
+	assert.False(t, results[1].Running)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, "Hung", results[2].Label)
+	assert.False(t, results[2].Running)
+	assert.ErrorIs(t, results[2].Err, ErrTimeout)
+}
+
+func TestNetworks(t *testing.T) {
+	slogs.Init("info")
+
+	services := []Service{
+		{Label: "Running", Client: &fakeService{}},
+		{Label: "Stopped", Client: &fakeService{err: errors.New("connection refused")}},
+	}
+
+	results := Networks(services, time.Second)
+	assert.Equal(t, Result{Label: "Running", Running: true}, results[0])
+	assert.False(t, results[1].Running)
+}
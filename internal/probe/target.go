@@ -0,0 +1,204 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/chia-network/go-chia-libs/pkg/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// serviceKeys are the names used in the per service flags, in the same order as Services returns them
+var serviceKeys = []string{"daemon", "full-node", "wallet", "farmer", "harvester", "crawler", "data-layer", "timelord"}
+
+// Endpoint overrides where a single service is reached. Empty values keep the value from the config.
+type Endpoint struct {
+	Host string
+	Port uint16
+}
+
+// Target describes where to reach the services. The zero value uses the local CHIA_ROOT, the same as
+// rpc.WithAutoConfig().
+type Target struct {
+	// Host replaces self_hostname for every service
+	Host string
+	// CertDir is a copy of a node's config/ssl directory, used instead of the certs in CHIA_ROOT
+	CertDir string
+	// Endpoints override the host and port of individual services, keyed by service name, such as "full-node"
+	Endpoints map[string]Endpoint
+}
+
+// AddFlags adds --host, --cert-dir, --inventory, and --<service>-host/port flags to flags, bound to viper keys with
+// prefix
+func AddFlags(flags *pflag.FlagSet, prefix string) {
+	flags.String("host", "", "Host to reach the services on (default is self_hostname from the config)")
+	flags.String("cert-dir", "", "Directory with a copy of the node's config/ssl directory, for connecting to a remote node")
+	flags.String("inventory", "", "Inventory file listing nodes to probe together, instead of a single node")
+	cobra.CheckErr(viper.BindPFlag(prefix+"-host", flags.Lookup("host")))
+	cobra.CheckErr(viper.BindPFlag(prefix+"-cert-dir", flags.Lookup("cert-dir")))
+	cobra.CheckErr(viper.BindPFlag(prefix+"-inventory", flags.Lookup("inventory")))
+
+	for _, service := range serviceKeys {
+		flags.String(service+"-host", "", fmt.Sprintf("Host to reach the %s on, overriding --host", service))
+		flags.Uint16(service+"-port", 0, fmt.Sprintf("RPC port of the %s (default is the port from the config)", service))
+		cobra.CheckErr(viper.BindPFlag(fmt.Sprintf("%s-%s-host", prefix, service), flags.Lookup(service+"-host")))
+		cobra.CheckErr(viper.BindPFlag(fmt.Sprintf("%s-%s-port", prefix, service), flags.Lookup(service+"-port")))
+	}
+}
+
+// TargetFromFlags returns the target described by the flags added with AddFlags
+func TargetFromFlags(prefix string) Target {
+	target := Target{
+		Host:      viper.GetString(prefix + "-host"),
+		CertDir:   viper.GetString(prefix + "-cert-dir"),
+		Endpoints: map[string]Endpoint{},
+	}
+	for _, service := range serviceKeys {
+		endpoint := Endpoint{
+			Host: viper.GetString(fmt.Sprintf("%s-%s-host", prefix, service)),
+			Port: viper.GetUint16(fmt.Sprintf("%s-%s-port", prefix, service)),
+		}
+		if endpoint != (Endpoint{}) {
+			target.Endpoints[service] = endpoint
+		}
+	}
+	return target
+}
+
+// IsLocal returns true when the target does not override anything, so the local CHIA_ROOT is used as is
+func (t Target) IsLocal() bool {
+	return t.Host == "" && t.CertDir == "" && len(t.Endpoints) == 0
+}
+
+// Config returns the chia config used to reach the target. When the cert dir sits in a copy of a node's config
+// directory, that node's config.yaml is used, so its ports and selected network apply. Otherwise, the local config
+// is used, falling back to the defaults when there isn't one. Host and cert dir overrides are applied, but not the
+// per service endpoints.
+func (t Target) Config() (*config.ChiaConfig, error) {
+	if t.IsLocal() {
+		return config.GetChiaConfig()
+	}
+
+	var cfg *config.ChiaConfig
+	var err error
+	if remoteConfig := t.remoteConfigPath(); remoteConfig != "" {
+		cfg, err = config.LoadConfigAtRoot(remoteConfig, t.CertDir)
+	} else {
+		cfg, err = config.GetChiaConfig()
+		if err != nil && errors.Is(err, os.ErrNotExist) {
+			cfg, err = config.LoadDefaultConfig()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	if t.Host != "" {
+		cfg.SelfHostname = t.Host
+	}
+	if t.CertDir != "" {
+		// SSL paths in the config are relative to CHIA_ROOT and start with config/ssl/. Rooting them in the cert dir
+		// instead finds the copied certs.
+		cfg.ChiaRoot = t.CertDir
+		for _, ssl := range []*config.SSLConfig{
+			&cfg.DaemonSSL,
+			&cfg.FullNode.SSL,
+			&cfg.Wallet.SSL,
+			&cfg.Farmer.SSL,
+			&cfg.Harvester.SSL,
+			&cfg.Seeder.CrawlerConfig.SSL,
+			&cfg.DataLayer.SSL,
+			&cfg.Timelord.SSL,
+		} {
+			ssl.PrivateCRT = trimSSLPrefix(ssl.PrivateCRT)
+			ssl.PrivateKey = trimSSLPrefix(ssl.PrivateKey)
+			ssl.PublicCRT = trimSSLPrefix(ssl.PublicCRT)
+			ssl.PublicKey = trimSSLPrefix(ssl.PublicKey)
+		}
+		for _, ca := range []*config.CAConfig{&cfg.PrivateSSLCA, &cfg.ChiaSSLCA} {
+			ca.Crt = trimSSLPrefix(ca.Crt)
+			ca.Key = trimSSLPrefix(ca.Key)
+		}
+	}
+
+	return cfg, nil
+}
+
+// HasNodeConfig returns true when Config returns the target's own config: the local config for a target on this
+// machine, or the copy of a remote node's config.yaml next to its cert dir. Otherwise, Config falls back to the local
+// config, which says nothing about the network the remote node is on.
+func (t Target) HasNodeConfig() bool {
+	return (t.Host == "" && t.CertDir == "") || t.remoteConfigPath() != ""
+}
+
+// remoteConfigPath returns the path of the config.yaml in the copy of the node's config directory that holds the cert
+// dir, or an empty string if there isn't one
+func (t Target) remoteConfigPath() string {
+	if t.CertDir == "" {
+		return ""
+	}
+	remoteConfig := filepath.Join(t.CertDir, "..", "config.yaml")
+	if _, err := os.Stat(remoteConfig); err != nil {
+		return ""
+	}
+	return remoteConfig
+}
+
+// serviceConfig returns a copy of cfg with the endpoint override for a service applied
+func serviceConfig(cfg config.ChiaConfig, service string, endpoint Endpoint) config.ChiaConfig {
+	if endpoint.Host != "" {
+		cfg.SelfHostname = endpoint.Host
+	}
+	if endpoint.Port == 0 {
+		return cfg
+	}
+	switch service {
+	case "daemon":
+		cfg.DaemonPort = endpoint.Port
+	case "full-node":
+		cfg.FullNode.RPCPort = endpoint.Port
+	case "wallet":
+		cfg.Wallet.RPCPort = endpoint.Port
+	case "farmer":
+		cfg.Farmer.RPCPort = endpoint.Port
+	case "harvester":
+		cfg.Harvester.RPCPort = endpoint.Port
+	case "crawler":
+		cfg.Seeder.CrawlerConfig.RPCPort = endpoint.Port
+	case "data-layer":
+		cfg.DataLayer.RPCPort = endpoint.Port
+	case "timelord":
+		cfg.Timelord.RPCPort = endpoint.Port
+	}
+	return cfg
+}
+
+func trimSSLPrefix(sslPath string) string {
+	return strings.TrimPrefix(filepath.ToSlash(sslPath), "config/ssl/")
+}
+
+// configOptions returns the rpc config option to use for each service, in the same order as serviceKeys
+func (t Target) configOptions() ([]rpc.ConfigOptionFunc, error) {
+	options := make([]rpc.ConfigOptionFunc, len(serviceKeys))
+	if t.IsLocal() {
+		for i := range options {
+			options[i] = rpc.WithAutoConfig()
+		}
+		return options, nil
+	}
+
+	cfg, err := t.Config()
+	if err != nil {
+		return nil, err
+	}
+	for i, service := range serviceKeys {
+		options[i] = rpc.WithManualConfig(serviceConfig(*cfg, service, t.Endpoints[service]))
+	}
+	return options, nil
+}
@@ -0,0 +1,71 @@
+package probe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chia-network/go-chia-libs/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetConfig_CertDir(t *testing.T) {
+	// A copy of a remote node's config directory, with its config.yaml next to the ssl directory
+	configDir := filepath.Join(t.TempDir(), "config")
+	certDir := filepath.Join(configDir, "ssl")
+	assert.NoError(t, os.MkdirAll(certDir, 0755))
+
+	remoteCfg, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	remoteCfg.FullNode.RPCPort = 18555
+	remoteCfg.FullNode.SSL.PrivateCRT = "config/ssl/full_node/private_full_node.crt"
+	remoteCfg.PrivateSSLCA.Crt = "config/ssl/ca/private_ca.crt"
+	assert.NoError(t, remoteCfg.SavePath(filepath.Join(configDir, "config.yaml")))
+
+	target := Target{Host: "10.0.0.5", CertDir: certDir}
+	assert.False(t, target.IsLocal())
+
+	cfg, err := target.Config()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", cfg.SelfHostname)
+	assert.Equal(t, certDir, cfg.ChiaRoot)
+	assert.Equal(t, uint16(18555), cfg.FullNode.RPCPort)
+	assert.Equal(t, "full_node/private_full_node.crt", cfg.FullNode.SSL.PrivateCRT)
+	assert.Equal(t, "ca/private_ca.crt", cfg.PrivateSSLCA.Crt)
+}
+
+func TestTargetHasNodeConfig(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "config")
+	certDir := filepath.Join(configDir, "ssl")
+	assert.NoError(t, os.MkdirAll(certDir, 0755))
+
+	assert.True(t, Target{}.HasNodeConfig())
+	assert.True(t, Target{Endpoints: map[string]Endpoint{"harvester": {Host: "10.0.0.6"}}}.HasNodeConfig())
+
+	// Without a copy of the remote node's config.yaml, the local config is used, which is not the node's
+	assert.False(t, Target{Host: "10.0.0.5"}.HasNodeConfig())
+	assert.False(t, Target{Host: "10.0.0.5", CertDir: certDir}.HasNodeConfig())
+
+	cfg, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.SavePath(filepath.Join(configDir, "config.yaml")))
+	assert.True(t, Target{Host: "10.0.0.5", CertDir: certDir}.HasNodeConfig())
+}
+
+func TestServiceConfig(t *testing.T) {
+	cfg, err := config.LoadDefaultConfig()
+	assert.NoError(t, err)
+	cfg.SelfHostname = "10.0.0.5"
+
+	harvester := serviceConfig(*cfg, "harvester", Endpoint{Host: "10.0.0.6", Port: 18560})
+	assert.Equal(t, "10.0.0.6", harvester.SelfHostname)
+	assert.Equal(t, uint16(18560), harvester.Harvester.RPCPort)
+
+	daemon := serviceConfig(*cfg, "daemon", Endpoint{Port: 55401})
+	assert.Equal(t, "10.0.0.5", daemon.SelfHostname)
+	assert.Equal(t, uint16(55401), daemon.DaemonPort)
+
+	// The original config is not modified
+	assert.Equal(t, "10.0.0.5", cfg.SelfHostname)
+	assert.Equal(t, cfg.Harvester.RPCPort, serviceConfig(*cfg, "full-node", Endpoint{Port: 1}).Harvester.RPCPort)
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"fmt"
+)
+
+// HumanReadableSize converts bytes into a human-friendly format (KB, MB, GB, etc.)
+func HumanReadableSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}